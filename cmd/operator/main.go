@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,11 +14,14 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
 
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/autosnapshot"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/logx"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/provider"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftpeers"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/restore"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/snapshot"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/unseal"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/version"
 
 	_ "github.com/Chapsvision-dev/vault-raft-backup-restore/internal/provider/azure"
@@ -24,17 +29,25 @@ import (
 
 // Test seams — overridden in unit tests. Keep signatures in sync with packages.
 var (
-	loadConfig  func() (config.Config, error)                                                   = config.Load
-	newProvider func(name string, cfg any) (provider.Provider, error)                           = provider.New
-	snapCreate  func(context.Context, config.Config, snapshot.Options) (snapshot.Result, error) = snapshot.Create
-	restoreRun  func(context.Context, config.Config, provider.Provider, restore.Options) error  = restore.Run
-	exit        func(int)                                                                       = os.Exit
+	loadConfig  func() (config.Config, error)                                                                    = config.Load
+	newProvider func(name string, cfg any) (provider.Provider, error)                                            = provider.New
+	snapCreate  func(context.Context, config.Config, snapshot.Options) (snapshot.Result, error)                  = snapshot.Create
+	restoreRun  func(context.Context, config.Config, provider.Provider, restore.Options) (restore.Result, error) = restore.Run
+	autoSnap    func(context.Context, config.Config, string, string, io.Writer) error                            = autosnapshot.Run
+	unsealRun   func(context.Context, config.Config, []string) (unseal.Result, error)                            = unseal.Run
+	raftRun     func(context.Context, config.Config, string, []string, io.Writer) error                          = raftpeers.Run
+	exit        func(int)                                                                                        = os.Exit
 )
 
 const usage = `
 Usage:
   operator backup  [source] [targetPrefix]
   operator restore [remoteKey] [localFile]
+  operator auto-snapshot list|get|apply|delete|status [name]   (Vault Enterprise)
+  operator raft    peers | remove-peer <node_id>...|--stale | join <node_addr>... [--leader <api_addr>]
+  operator verify  [localFile|remoteKey]   (offline SHA256SUMS check; default RESTORE_SOURCE)
+  operator inspect [localFile|remoteKey] [--json]   (meta.json, size, per-file digests)
+  operator unseal  [keyFile...]   (key shares one per line; "-" or no file reads stdin)
   operator version | --version | -v
   operator help    | --help    | -h
 
@@ -42,11 +55,17 @@ Notes:
   - You can also set env vars:
       BACKUP_SOURCE, BACKUP_TARGET, RESTORE_SOURCE, RESTORE_TARGET
   - Provider is selected with BACKUP_PROVIDER (default: azure).
+  - auto-snapshot manages sys/storage/raft/snapshot-auto/config/<name> (default AUTO_SNAPSHOT_NAME)
+    writing to the AZURE_STORAGE_ACCOUNT/AZURE_STORAGE_CONTAINER used by backup and restore.
   - Vault address/token: VAULT_ADDR (default http://vault-hashicorp.localhost), VAULT_TOKEN
+  - unseal submits key shares to sys/unseal on every node listed in VAULT_ADDR (other
+    raft members are not discovered; with VAULT_TOKEN they are reported); keys are never logged.
+    Files may also come from UNSEAL_KEY_FILES; RESTORE_UNSEAL=true chains it after restore.
+  - Exit codes: 0 ok, 1 error, 2 usage, 3 backup skipped (BACKUP_HEALTH_POLICY=skip)
 `
 
 // main wires CLI -> config -> provider -> backup/restore.
-// Exit codes: 0 success, 1 runtime error, 2 usage error.
+// Exit codes: 0 success, 1 runtime error, 2 usage error, 3 backup skipped (cluster not ready).
 func main() {
 	_ = godotenv.Load() // best-effort
 	logx.InitFromEnv()
@@ -70,12 +89,75 @@ func main() {
 		exit(0)
 	}
 
+	ctx := withSignals(context.Background())
+
+	// Offline snapshot commands: config is only needed to fetch a remote key.
+	if action == "verify" {
+		runVerify(ctx, pickArgOrEnv(2, "RESTORE_SOURCE", ""), os.Stdout)
+		return
+	}
+	if action == "inspect" {
+		runInspect(ctx, os.Args[2:], os.Stdout)
+		return
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		log.Error().Err(err).Msg("config error")
 		exit(1)
 	}
 
+	// Vault-only commands: no storage provider needed.
+	if action == "auto-snapshot" {
+		err := autoSnap(ctx, cfg, argAt(2), pickArgOrEnv(3, "AUTO_SNAPSHOT_NAME", cfg.AutoSnapshot.Name), os.Stdout)
+		if errors.Is(err, autosnapshot.ErrUsage) {
+			fmt.Println(err)
+			exit(2)
+		}
+		if err != nil {
+			exit(1)
+		}
+		return
+	}
+
+	if action == "raft" {
+		var rest []string
+		if len(os.Args) > 3 {
+			rest = os.Args[3:]
+		}
+		err := raftRun(ctx, cfg, argAt(2), rest, os.Stdout)
+		if errors.Is(err, raftpeers.ErrUsage) {
+			fmt.Println(err)
+			exit(2)
+		}
+		if err != nil {
+			exit(1)
+		}
+		return
+	}
+
+	if action == "unseal" {
+		files := cfg.UnsealKeyFiles
+		if len(os.Args) > 2 {
+			files = os.Args[2:]
+		}
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+		keys, err := unseal.ReadKeys(files, os.Stdin)
+		if err != nil {
+			log.Error().Err(err).Str("action", "unseal").Msg("unseal keys")
+			exit(1)
+		}
+		res, err := unsealRun(ctx, cfg, keys)
+		if err != nil {
+			log.Error().Err(err).Str("action", "unseal").Strs("sealed", res.Sealed()).Msg("unseal failed")
+			exit(1)
+		}
+		log.Info().Str("action", "unseal").Int("nodes", len(res.Nodes)).Msg("unseal OK")
+		return
+	}
+
 	// Build provider from config.
 	p, err := newProvider(cfg.Provider, cfg)
 	if err != nil {
@@ -83,8 +165,6 @@ func main() {
 		exit(1)
 	}
 
-	ctx := withSignals(context.Background())
-
 	switch action {
 	case "backup":
 		source := pickArgOrEnv(2, "BACKUP_SOURCE", cfg.BackupSource)
@@ -96,6 +176,10 @@ func main() {
 			RemotePrefix:    targetPrefix,
 			TimestampFormat: cfg.BackupTimestampFormat,
 		})
+		if errors.Is(err, snapshot.ErrSkipped) {
+			log.Warn().Err(err).Str("action", "snapshot").Str("state", string(res.Health.State)).Msg("snapshot skipped")
+			exit(3)
+		}
 		if err != nil {
 			log.Error().Err(err).Str("action", "snapshot").Msg("snapshot failed")
 			exit(1)
@@ -104,18 +188,33 @@ func main() {
 			Str("action", "snapshot").
 			Str("local", res.LocalPath).
 			Str("remote", res.RemoteKey).
+			Str("cluster_state", string(res.Health.State)).
+			Str("leader", res.Cluster.Leader).
+			Int("peers", len(res.Cluster.Peers)).
+			Uint64("raft_index", res.Cluster.RaftIndex).
+			Uint64("raft_term", res.Cluster.RaftTerm).
+			Str("vault_version", res.Cluster.VaultVersion).
 			Dur("elapsed_ms", time.Since(start)).
 			Msg("vault raft snapshot OK")
 
 		upStart := time.Now()
-		if err := p.Backup(ctx, res.LocalPath, res.RemoteKey); err != nil {
+		if err := p.Backup(ctx, res.LocalPath, res.RemoteKey, res.Digests); err != nil {
 			log.Error().Err(err).Str("action", "upload").Str("remote", res.RemoteKey).Msg("upload failed")
 			exit(1)
 		}
+		if res.ClusterFile != "" {
+			recordKey := res.RemoteKey + snapshot.ClusterFileSuffix
+			if err := p.Backup(ctx, res.ClusterFile, recordKey, nil); err != nil {
+				log.Error().Err(err).Str("action", "upload").Str("remote", recordKey).Msg("cluster record upload failed")
+				exit(1)
+			}
+			_ = os.Remove(res.ClusterFile)
+		}
 		log.Info().
 			Str("action", "upload").
 			Str("provider", cfg.Provider).
 			Str("remote", res.RemoteKey).
+			Str("digests", res.Digests.String()).
 			Dur("elapsed_ms", time.Since(upStart)).
 			Msg("backup OK")
 
@@ -124,20 +223,34 @@ func main() {
 		target := pickArgOrEnv(3, "RESTORE_TARGET", cfg.RestoreTarget) // local file (optional)
 
 		start := time.Now()
-		// Force restore can be toggled via env if you veux (OPTIONAL): VAULT_SNAPSHOT_FORCE=true
+		// VAULT_SNAPSHOT_FORCE=true requests snapshot-force; it is only honored when
+		// confirmed with RESTORE_CONFIRM_CLUSTER_ID.
 		force := strings.EqualFold(os.Getenv("VAULT_SNAPSHOT_FORCE"), "true")
-		if err := restoreRun(ctx, cfg, p, restore.Options{
-			RemoteKey: source,
-			LocalPath: target,
-			Force:     force,
-		}); err != nil {
-			log.Error().Err(err).Str("action", "restore").Str("remote", source).Msg("restore failed")
+		res, err := restoreRun(ctx, cfg, p, restore.Options{
+			RemoteKey:        source,
+			LocalPath:        target,
+			Force:            force,
+			ConfirmClusterID: cfg.RestoreConfirmCluster,
+			SafetySnapshot:   cfg.RestoreSafetySnapshot,
+			RollbackPrefix:   cfg.RestoreRollbackPrefix,
+		})
+		if err != nil {
+			ev := log.Error().Err(err).Str("action", "restore").Str("remote", source)
+			if res.RollbackKey != "" {
+				ev = ev.Str("rollback_key", res.RollbackKey)
+			}
+			ev.Msg("restore failed")
 			exit(1)
 		}
 		log.Info().
 			Str("action", "restore").
 			Str("provider", cfg.Provider).
 			Str("remote", source).
+			Str("rollback_key", res.RollbackKey).
+			Str("relation", res.Identity.Relation).
+			Bool("force", res.Identity.Force).
+			Bool("verified", res.Verified).
+			Str("digests", res.Digests.String()).
 			Dur("elapsed_ms", time.Since(start)).
 			Msg("restore OK")
 
@@ -147,6 +260,14 @@ func main() {
 	}
 }
 
+// argAt returns os.Args[idx], or "" when absent.
+func argAt(idx int) string {
+	if len(os.Args) > idx {
+		return os.Args[idx]
+	}
+	return ""
+}
+
 func pickArgOrEnv(idx int, env string, def string) string {
 	if len(os.Args) > idx && os.Args[idx] != "" {
 		return os.Args[idx]
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
+)
+
+// snapshotFile resolves ref to a local snapshot file. An existing file is used in place;
+// anything else is a provider key downloaded to a temporary file (removed by cleanup).
+// Config is only loaded in the remote case, so offline checks need no Vault settings.
+func snapshotFile(ctx context.Context, ref string) (string, func(), error) {
+	noop := func() {}
+	if ref == "" {
+		return "", noop, fmt.Errorf("no snapshot given (local file or remote key)")
+	}
+	if st, err := os.Stat(ref); err == nil && !st.IsDir() {
+		return ref, noop, nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", noop, fmt.Errorf("config: %w", err)
+	}
+	p, err := newProvider(cfg.Provider, cfg)
+	if err != nil {
+		return "", noop, fmt.Errorf("provider init: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "vault-snapshot-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	local := filepath.Join(dir, filepath.Base(ref))
+	log.Info().Str("action", "download").Str("provider", cfg.Provider).Str("remote", ref).Str("local", local).Msg("fetching snapshot")
+	if _, err := p.Restore(ctx, ref, local); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("download from provider: %w", err)
+	}
+	return local, cleanup, nil
+}
+
+// runVerify implements "operator verify": walk the archive and check SHA256SUMS.
+// Exit 0 when every file matches, 1 otherwise.
+func runVerify(ctx context.Context, ref string, out io.Writer) {
+	path, cleanup, err := snapshotFile(ctx, ref)
+	if err != nil {
+		log.Error().Err(err).Str("action", "verify").Str("snapshot", ref).Msg("cannot open snapshot")
+		exit(1)
+		return
+	}
+	defer cleanup()
+
+	report, err := raftsnap.Verify(path)
+	report.Path = ref
+	writeJSON(out, report)
+
+	for _, f := range report.Files {
+		ev := log.Info()
+		if f.Status != raftsnap.StatusOK {
+			ev = log.Warn()
+		}
+		ev.Str("action", "verify").Str("file", f.Name).Int64("size", f.Size).Str("status", f.Status).Msg("snapshot file")
+	}
+	if err != nil || !report.OK {
+		log.Error().Err(err).Str("action", "verify").Str("snapshot", ref).Msg("snapshot verification failed")
+		exit(1)
+		return
+	}
+	log.Info().Str("action", "verify").Str("snapshot", ref).Int64("size", report.Size).Msg("snapshot verification OK")
+}
+
+// runInspect implements "operator inspect": print meta.json, archive size and per-file digests.
+func runInspect(ctx context.Context, args []string, out io.Writer) {
+	asJSON := false
+	ref := ""
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			asJSON = true
+		case ref == "":
+			ref = a
+		}
+	}
+	if ref == "" {
+		ref = os.Getenv("RESTORE_SOURCE")
+	}
+
+	path, cleanup, err := snapshotFile(ctx, ref)
+	if err != nil {
+		log.Error().Err(err).Str("action", "inspect").Str("snapshot", ref).Msg("cannot open snapshot")
+		exit(1)
+		return
+	}
+	defer cleanup()
+
+	a, err := raftsnap.Scan(path, raftsnap.ScanOptions{Stats: true})
+	if err != nil {
+		log.Error().Err(err).Str("action", "inspect").Str("snapshot", ref).Msg("cannot read snapshot")
+		exit(1)
+		return
+	}
+	a.Path = ref
+	if asJSON {
+		writeJSON(out, a)
+		return
+	}
+	printArchive(out, a)
+}
+
+// printArchive renders an inspected archive for humans.
+func printArchive(out io.Writer, a raftsnap.Archive) {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer func() { _ = tw.Flush() }()
+
+	fmt.Fprintf(tw, "Snapshot:\t%s\n", a.Path)
+	fmt.Fprintf(tw, "Size:\t%d bytes\n", a.Size)
+	if m := a.Meta; m != nil {
+		fmt.Fprintf(tw, "ID:\t%s\n", m.ID)
+		fmt.Fprintf(tw, "Format version:\t%d\n", m.Version)
+		fmt.Fprintf(tw, "Raft index:\t%d\n", m.Index)
+		fmt.Fprintf(tw, "Raft term:\t%d\n", m.Term)
+		fmt.Fprintf(tw, "Config index:\t%d\n", m.ConfigurationIndex)
+		fmt.Fprintf(tw, "State size:\t%d bytes\n", m.Size)
+		fmt.Fprintf(tw, "Peers:\t%d\n", len(m.Configuration.Servers))
+		for _, p := range m.Configuration.Servers {
+			suffrage := "voter"
+			if !p.Voter() {
+				suffrage = "non-voter"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", p.ID, p.Address, suffrage)
+		}
+	} else {
+		fmt.Fprintf(tw, "Meta:\t%s not found\n", raftsnap.FileMeta)
+	}
+	fmt.Fprintf(tw, "Files:\t%d\n", len(a.Members))
+	for _, f := range a.Members {
+		fmt.Fprintf(tw, "  %s\t%d bytes\tsha256:%s\n", f.Name, f.Size, f.SHA256)
+	}
+
+	switch {
+	case a.StateError != "":
+		fmt.Fprintf(tw, "Contents:\tunreadable: %s\n", a.StateError)
+	case a.State != nil:
+		st := a.State
+		fmt.Fprintf(tw, "Contents:\t%d entries, %d key bytes, %d value bytes (values are encrypted)\n",
+			st.Total.Entries, st.Total.KeyBytes, st.Total.ValueBytes)
+		for _, p := range []string{raftsnap.PrefixCore, raftsnap.PrefixSys, raftsnap.PrefixPolicies,
+			raftsnap.PrefixLogical, raftsnap.PrefixAuth, raftsnap.PrefixOther} {
+			ps := st.Prefixes[p]
+			fmt.Fprintf(tw, "  %s\t%d entries\t%d bytes\n", p, ps.Entries, ps.KeyBytes+ps.ValueBytes)
+		}
+		mounts := make([]string, 0, len(st.Mounts))
+		for m := range st.Mounts {
+			mounts = append(mounts, m)
+		}
+		sort.Strings(mounts)
+		for _, m := range mounts {
+			ms := st.Mounts[m]
+			fmt.Fprintf(tw, "  %s\t%d entries\t%d bytes\n", m, ms.Entries, ms.KeyBytes+ms.ValueBytes)
+		}
+	}
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(out io.Writer, v any) {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Error().Err(err).Msg("write output")
+	}
+}
@@ -1,19 +1,32 @@
 package main
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/autosnapshot"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/provider"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftpeers"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/restore"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/snapshot"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/unseal"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
 )
 
 /* ----------------------------- test harness ----------------------------- */
@@ -102,6 +115,9 @@ func resetSeams() {
 	newProvider = provider.New
 	snapCreate = snapshot.Create
 	restoreRun = restore.Run
+	autoSnap = autosnapshot.Run
+	unsealRun = unseal.Run
+	raftRun = raftpeers.Run
 }
 
 /* --------------------------------- tests -------------------------------- */
@@ -187,9 +203,9 @@ func TestRestore_UsesEnvWhenNoArgs(t *testing.T) {
 	}
 
 	var got restore.Options
-	restoreRun = func(ctx context.Context, cfg config.Config, p provider.Provider, opts restore.Options) error {
+	restoreRun = func(ctx context.Context, cfg config.Config, p provider.Provider, opts restore.Options) (restore.Result, error) {
 		got = opts
-		return errors.New("stop")
+		return restore.Result{}, errors.New("stop")
 	}
 
 	code := mustExitCode(t, func() { main() })
@@ -201,6 +217,116 @@ func TestRestore_UsesEnvWhenNoArgs(t *testing.T) {
 	}
 }
 
+// 3b) Backup: health gate skip -> exit code 3 (distinct from runtime errors)
+func TestBackup_SkippedExitsWith3(t *testing.T) {
+	resetSeams()
+	defer patchExit(t)()
+	defer withArgs(t, []string{"backup"})()
+
+	loadConfig = func() (config.Config, error) { return config.Config{Provider: "azure"}, nil }
+	newProvider = func(_ string, _ any) (provider.Provider, error) { return dummyProvider{}, nil }
+	snapCreate = func(ctx context.Context, cfg config.Config, opts snapshot.Options) (snapshot.Result, error) {
+		return snapshot.Result{}, fmt.Errorf("%w: vault is sealed", snapshot.ErrSkipped)
+	}
+
+	code := mustExitCode(t, func() { main() })
+	if code != 3 {
+		t.Fatalf("want exit 3 for skipped backup, got %d", code)
+	}
+}
+
+// 3c) auto-snapshot: no storage provider is built; unknown subcommand -> exit 2
+func TestAutoSnapshot_SkipsProviderAndRejectsUnknown(t *testing.T) {
+	resetSeams()
+	defer patchExit(t)()
+	defer withArgs(t, []string{"auto-snapshot", "bogus"})()
+
+	loadConfig = func() (config.Config, error) { return config.Config{Provider: "azure"}, nil }
+	newProvider = func(_ string, _ any) (provider.Provider, error) {
+		t.Fatal("provider must not be built for auto-snapshot")
+		return nil, nil
+	}
+
+	code := mustExitCode(t, func() { main() })
+	if code != 2 {
+		t.Fatalf("want exit 2 for unknown subcommand, got %d", code)
+	}
+}
+
+// 3d) verify: a local file is checked offline (no config); a corrupt archive exits 1
+func TestVerify_LocalFileNeedsNoConfig(t *testing.T) {
+	resetSeams()
+	defer patchExit(t)()
+	bad := filepath.Join(t.TempDir(), "bad.snap")
+	if err := os.WriteFile(bad, []byte("<html>proxy error</html>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer withArgs(t, []string{"verify", bad})()
+
+	loadConfig = func() (config.Config, error) {
+		t.Fatal("config must not be loaded for a local file")
+		return config.Config{}, nil
+	}
+
+	code := mustExitCode(t, func() { main() })
+	if code != 1 {
+		t.Fatalf("want exit 1 for a corrupt snapshot, got %d", code)
+	}
+}
+
+// 3e) inspect: a local archive prints meta, per-file digests and contents, as text or --json
+func TestInspect_LocalArchive(t *testing.T) {
+	resetSeams()
+	defer patchExit(t)()
+	snap := snapshotFixture(t)
+	loadConfig = func() (config.Config, error) {
+		t.Fatal("config must not be loaded for a local file")
+		return config.Config{}, nil
+	}
+
+	defer withArgs(t, []string{"inspect", snap, "--json"})()
+	restoreOut := captureStdout(t)
+	main()
+	var a raftsnap.Archive
+	if err := json.Unmarshal([]byte(restoreOut()), &a); err != nil {
+		t.Fatalf("--json output is not an archive: %v", err)
+	}
+	if a.Path != snap || a.Meta == nil || a.Meta.Index != 120 || len(a.Members) != 3 {
+		t.Fatalf("unexpected archive: %+v", a)
+	}
+	if a.State == nil || a.State.Total.Entries != 2 || a.State.Prefixes[raftsnap.PrefixLogical].Entries != 1 {
+		t.Fatalf("unexpected state stats: %+v", a.State)
+	}
+
+	defer withArgs(t, []string{"inspect", snap})()
+	restoreOut = captureStdout(t)
+	main()
+	out := restoreOut()
+	// tabwriter aligns every value on one column; compare with whitespace collapsed.
+	lines := strings.Split(out, "\n")
+	if col := strings.Index(lines[0], snap); col < 0 || strings.Index(lines[4], "120") != col {
+		t.Fatalf("values are not aligned:\n%s", out)
+	}
+	var flat []string
+	for _, l := range lines {
+		flat = append(flat, strings.Join(strings.Fields(l), " "))
+	}
+	text := strings.Join(flat, "\n")
+	for _, want := range []string{
+		"Snapshot: " + snap,
+		"Raft index: 120",
+		"Peers: 2",
+		"vault-1 vault-1:8201 non-voter",
+		"state.bin 79 bytes sha256:" + a.Members[1].SHA256,
+		"Contents: 2 entries, 37 key bytes, 32 value bytes",
+		"logical/1111-aaaa 1 entries 42 bytes",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("inspect output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
 // 4) pickArgOrEnv: precedence Arg > Env > Default
 func TestPickArgOrEnv_Precedence(t *testing.T) {
 	// Build synthetic argv: program, subcmd, ARGVAL
@@ -252,6 +378,55 @@ func TestWithSignals_CancelsOnInterrupt(t *testing.T) {
 
 type dummyProvider struct{}
 
-func (dummyProvider) Name() string                                            { return "dummy" }
-func (dummyProvider) Backup(ctx context.Context, local, remote string) error  { return nil }
-func (dummyProvider) Restore(ctx context.Context, remote, local string) error { return nil }
+func (dummyProvider) Name() string { return "dummy" }
+func (dummyProvider) Backup(ctx context.Context, local, remote string, sums util.Digests) error {
+	return nil
+}
+func (dummyProvider) Restore(ctx context.Context, remote, local string) (util.Digests, error) {
+	return nil, nil
+}
+
+// snapshotFixture writes a Vault raft snapshot archive with two peers and two state entries.
+func snapshotFixture(t *testing.T) string {
+	t.Helper()
+	meta := `{"Version":1,"ID":"2-120-1700000000000","Index":120,"Term":2,"Configuration":{"Servers":[` +
+		`{"Suffrage":0,"ID":"vault-0","Address":"vault-0:8201"},{"Suffrage":1,"ID":"vault-1","Address":"vault-1:8201"}]},"ConfigurationIndex":1,"Size":42}`
+	var state []byte
+	for _, key := range []string{"core/mounts", "logical/1111-aaaa/data/app"} {
+		// Length-delimited StorageEntry{key: 1, value: 2} with a 16-byte value.
+		msg := append([]byte{0x0a}, binary.AppendUvarint(nil, uint64(len(key)))...)
+		msg = append(append(msg, key...), 0x12, 16)
+		msg = append(msg, make([]byte, 16)...)
+		state = append(append(state, binary.AppendUvarint(nil, uint64(len(msg)))...), msg...)
+	}
+	members := [][2]string{{raftsnap.FileMeta, meta}, {raftsnap.FileState, string(state)}}
+	var sums strings.Builder
+	for _, m := range members {
+		h := sha256.Sum256([]byte(m[1]))
+		sums.WriteString(hex.EncodeToString(h[:]) + "  " + m[0] + "\n")
+	}
+	members = append(members, [2]string{raftsnap.FileSums, sums.String()})
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, m := range members {
+		if err := tw.WriteHeader(&tar.Header{Name: m[0], Mode: 0o600, Size: int64(len(m[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(m[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), "fixture.snap")
+	if err := os.WriteFile(p, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
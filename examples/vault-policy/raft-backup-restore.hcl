@@ -1,8 +1,11 @@
 # Vault Policy for Raft Backup & Restore Operations
 # Apply with: vault policy write raft-backup-restore raft-backup-restore.hcl
+#
+# The operator checks these capabilities up front via sys/capabilities-self
+# (granted by the built-in "default" policy) and names any missing one.
 
 # GET /v1/sys/storage/raft/snapshot
-# Required for creating backups
+# Required for creating backups, and for restores with RESTORE_SAFETY_SNAPSHOT=true
 path "sys/storage/raft/snapshot" {
   capabilities = ["read"]
 }
@@ -18,3 +21,36 @@ path "sys/storage/raft/snapshot" {
 path "sys/storage/raft/snapshot-force" {
   capabilities = ["update"]
 }
+
+# GET /v1/sys/storage/raft/configuration
+# GET /v1/sys/storage/raft/autopilot/state
+# Optional: peer list, leader and quorum health recorded with each backup
+# (uploaded as <key>.cluster.json);
+# the configuration is also compared with a snapshot's peers before restore
+path "sys/storage/raft/configuration" {
+  capabilities = ["read"]
+}
+
+path "sys/storage/raft/autopilot/state" {
+  capabilities = ["read"]
+}
+
+# Vault Enterprise automated snapshots (operator auto-snapshot)
+# Optional: list/get/apply/delete configurations and read their status
+path "sys/storage/raft/snapshot-auto/config" {
+  capabilities = ["list"]
+}
+
+path "sys/storage/raft/snapshot-auto/config/*" {
+  capabilities = ["create", "read", "update", "delete"]
+}
+
+path "sys/storage/raft/snapshot-auto/status/*" {
+  capabilities = ["read"]
+}
+
+# POST /v1/sys/storage/raft/remove-peer
+# Optional: operator raft remove-peer (join is unauthenticated on the joining node)
+path "sys/storage/raft/remove-peer" {
+  capabilities = ["update"]
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// approleProvider implements Vault auth using the AppRole method.
+type approleProvider struct {
+	cfg config.AuthConfig
+	vc  *vault.Client
+	ro  retry.Options
+
+	// unwrapped caches the secret-id once a wrapping token was consumed (single use).
+	unwrapped string
+}
+
+// newApproleProvider validates configuration and returns a provider.
+// Role-id and a secret-id source (env or file) are mandatory.
+func newApproleProvider(cfg config.Config, vc *vault.Client) (*approleProvider, error) {
+	if strings.TrimSpace(cfg.Auth.RoleID) == "" {
+		return nil, errors.New("approle auth requires role-id")
+	}
+	if strings.TrimSpace(cfg.Auth.SecretID) == "" && strings.TrimSpace(cfg.Auth.SecretIDPath) == "" {
+		return nil, errors.New("approle auth requires secret-id or secret-id file")
+	}
+	if vc == nil {
+		return nil, errors.New("approle auth requires a vault client")
+	}
+	return &approleProvider{cfg: cfg.Auth, vc: vc.WithNamespace(cfg.Auth.AuthNamespace), ro: cfg.RetryOptions()}, nil
+}
+
+// Acquire logs in with role-id/secret-id and returns a Vault client token.
+func (p *approleProvider) Acquire(ctx context.Context) (string, error) {
+	return login(ctx, p.vc, p.ro, "approle", p.cfg.Mount, "", func(ctx context.Context) (any, error) {
+		secretID, err := p.secretID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{
+			"role_id":   p.cfg.RoleID,
+			"secret_id": secretID,
+		}, nil
+	})
+}
+
+// secretID reads the secret-id from file or env, unwrapping it when configured.
+func (p *approleProvider) secretID(ctx context.Context) (string, error) {
+	if p.unwrapped != "" {
+		return p.unwrapped, nil
+	}
+
+	raw := p.cfg.SecretID
+	if p.cfg.SecretIDPath != "" {
+		data, err := os.ReadFile(p.cfg.SecretIDPath)
+		if err != nil {
+			return "", fmt.Errorf("read secret-id: %w", err)
+		}
+		raw = string(data)
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("approle: empty secret-id")
+	}
+	if !p.cfg.SecretWrapped {
+		return raw, nil
+	}
+
+	var data struct {
+		SecretID string `json:"secret_id"`
+	}
+	if err := p.vc.Unwrap(ctx, raw, &data); err != nil {
+		return "", fmt.Errorf("approle: unwrap secret-id: %w", err)
+	}
+	if data.SecretID == "" {
+		return "", errors.New("approle: wrapped response has no secret_id")
+	}
+	log.Debug().
+		Str("action", "auth_unwrap").
+		Str("method", "approle").
+		Msg("secret-id unwrapped")
+	p.unwrapped = data.SecretID
+	return p.unwrapped, nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// 1) A wrapped secret-id is unwrapped once; later logins reuse it (the wrapping token is single use)
+func TestApproleProvider_UnwrapsOnce(t *testing.T) {
+	unwraps := 0
+	var secretIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/wrapping/unwrap":
+			if unwraps++; unwraps > 1 || r.Header.Get("X-Vault-Token") != "s.wrapping" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["wrapping token is not valid or does not exist"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"secret_id":"unwrapped-secret"}}`))
+		case "/v1/auth/approle/login":
+			var in map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			secretIDs = append(secretIDs, in["secret_id"])
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"s.approle"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretPath := filepath.Join(t.TempDir(), "secret-id")
+	if err := os.WriteFile(secretPath, []byte("s.wrapping\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{RetryMaxAttempts: 1, Auth: config.AuthConfig{
+		Method:        "approle",
+		Mount:         "approle",
+		RoleID:        "role",
+		SecretIDPath:  secretPath,
+		SecretWrapped: true,
+	}}
+	p, err := newApproleProvider(cfg, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := p.Acquire(context.Background())
+		if err != nil || token != "s.approle" {
+			t.Fatalf("login %d: token=%q err=%v", i+1, token, err)
+		}
+	}
+	if unwraps != 1 {
+		t.Fatalf("want sys/wrapping/unwrap called once, got %d", unwraps)
+	}
+	if len(secretIDs) != 2 || secretIDs[0] != "unwrapped-secret" || secretIDs[1] != "unwrapped-secret" {
+		t.Fatalf("logins did not reuse the unwrapped secret-id: %v", secretIDs)
+	}
+}
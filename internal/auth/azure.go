@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// Azure Instance Metadata Service defaults.
+const (
+	defaultIMDSEndpoint = "http://169.254.169.254"
+	imdsTokenPath       = "/metadata/identity/oauth2/token"
+	imdsComputePath     = "/metadata/instance/compute"
+	imdsTimeout         = 10 * time.Second
+)
+
+// azureProvider implements Vault auth using the Azure method.
+// The access token comes from the same identity as blob storage: a federated token file
+// (AKS workload identity) when present, otherwise the managed identity via IMDS.
+type azureProvider struct {
+	cfg  config.AuthConfig
+	az   config.AzureAuthConfig
+	vc   *vault.Client
+	ro   retry.Options
+	imds *http.Client
+}
+
+// newAzureProvider validates configuration and returns a provider.
+// The Vault role is mandatory.
+func newAzureProvider(cfg config.Config, vc *vault.Client) (*azureProvider, error) {
+	if strings.TrimSpace(cfg.Auth.Role) == "" {
+		return nil, errors.New("azure auth requires role")
+	}
+	if vc == nil {
+		return nil, errors.New("azure auth requires a vault client")
+	}
+	az := cfg.Auth.Azure
+	if strings.TrimSpace(az.IMDSEndpoint) == "" {
+		az.IMDSEndpoint = defaultIMDSEndpoint
+	}
+	return &azureProvider{
+		cfg:  cfg.Auth,
+		az:   az,
+		vc:   vc.WithNamespace(cfg.Auth.AuthNamespace),
+		ro:   cfg.RetryOptions(),
+		imds: &http.Client{Timeout: imdsTimeout},
+	}, nil
+}
+
+// Acquire obtains an Azure access token and exchanges it for a Vault client token.
+// The access token is fetched again before every login attempt.
+func (p *azureProvider) Acquire(ctx context.Context) (string, error) {
+	return login(ctx, p.vc, p.ro, "azure", p.cfg.Mount, p.cfg.Role, func(ctx context.Context) (any, error) {
+		var (
+			jwt    string
+			source string
+			err    error
+		)
+		if p.az.FederatedTokenFile != "" {
+			source = "workload_identity"
+			jwt, err = p.federatedToken(ctx)
+		} else {
+			source = "imds"
+			jwt, err = p.imdsToken(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("azure %s token: %w", source, err)
+		}
+
+		body := map[string]string{
+			"role": p.cfg.Role,
+			"jwt":  jwt,
+		}
+		p.addIdentity(ctx, source, body)
+		return body, nil
+	})
+}
+
+// federatedToken exchanges the projected federated token for an Entra ID access token.
+func (p *azureProvider) federatedToken(ctx context.Context) (string, error) {
+	cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientID:      p.az.ClientID,
+		TenantID:      p.az.TenantID,
+		TokenFilePath: p.az.FederatedTokenFile,
+	})
+	if err != nil {
+		return "", err
+	}
+	tok, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{strings.TrimRight(p.az.Resource, "/") + "/.default"},
+	})
+	if err != nil {
+		return "", err
+	}
+	return tok.Token, nil
+}
+
+// imdsToken requests a managed identity access token from IMDS.
+func (p *azureProvider) imdsToken(ctx context.Context) (string, error) {
+	q := url.Values{}
+	q.Set("api-version", "2018-02-01")
+	q.Set("resource", p.az.Resource)
+	if p.az.ClientID != "" {
+		q.Set("client_id", p.az.ClientID)
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.imdsGet(ctx, imdsTokenPath, q, &out); err != nil {
+		return "", err
+	}
+	if out.AccessToken == "" {
+		return "", errors.New("empty access_token")
+	}
+	return out.AccessToken, nil
+}
+
+// addIdentity fills the VM/VMSS fields Vault uses for bound_* checks.
+// Explicit settings win; on IMDS the missing ones are read from instance metadata (best effort).
+func (p *azureProvider) addIdentity(ctx context.Context, source string, body map[string]string) {
+	az := p.az
+	if source == "imds" && (az.SubscriptionID == "" || az.ResourceGroup == "") {
+		var compute struct {
+			SubscriptionID string `json:"subscriptionId"`
+			ResourceGroup  string `json:"resourceGroupName"`
+			Name           string `json:"name"`
+			VMScaleSetName string `json:"vmScaleSetName"`
+			ResourceID     string `json:"resourceId"`
+		}
+		q := url.Values{"api-version": {"2021-02-01"}}
+		if err := p.imdsGet(ctx, imdsComputePath, q, &compute); err != nil {
+			log.Debug().Err(err).Str("action", "auth_azure_metadata").Msg("instance metadata unavailable")
+		} else {
+			az.SubscriptionID = firstNonEmpty(az.SubscriptionID, compute.SubscriptionID)
+			az.ResourceGroup = firstNonEmpty(az.ResourceGroup, compute.ResourceGroup)
+			az.ResourceID = firstNonEmpty(az.ResourceID, compute.ResourceID)
+			if az.VMName == "" && az.VMSSName == "" {
+				if compute.VMScaleSetName != "" {
+					az.VMSSName = compute.VMScaleSetName
+				} else {
+					az.VMName = compute.Name
+				}
+			}
+		}
+	}
+
+	for k, v := range map[string]string{
+		"subscription_id":     az.SubscriptionID,
+		"resource_group_name": az.ResourceGroup,
+		"vm_name":             az.VMName,
+		"vmss_name":           az.VMSSName,
+		"resource_id":         az.ResourceID,
+	} {
+		if v != "" {
+			body[k] = v
+		}
+	}
+}
+
+// imdsGet performs a GET against IMDS and decodes the JSON response.
+func (p *azureProvider) imdsGet(ctx context.Context, path string, q url.Values, out any) error {
+	u := strings.TrimRight(p.az.IMDSEndpoint, "/") + path + "?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := p.imds.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("imds %s: %s (%s)", path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// 1) Managed identity token from a local IMDS stand-in is exchanged on auth/azure/login
+func TestAzureProvider_IMDSLogin(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case imdsTokenPath:
+			if r.URL.Query().Get("client_id") != "mi-client" || r.URL.Query().Get("resource") != "https://management.azure.com/" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"aad-token"}`))
+		case imdsComputePath:
+			_, _ = w.Write([]byte(`{"subscriptionId":"sub","resourceGroupName":"rg","name":"vm-1","vmScaleSetName":""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer imds.Close()
+
+	var got map[string]string
+	vaultSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/azure/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.azure"}}`))
+	}))
+	defer vaultSrv.Close()
+
+	vc, err := vault.NewClient(vault.Options{Addr: vaultSrv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{Auth: config.AuthConfig{
+		Method: "azure",
+		Mount:  "azure",
+		Role:   "backup",
+		Azure: config.AzureAuthConfig{
+			Resource:     "https://management.azure.com/",
+			ClientID:     "mi-client",
+			IMDSEndpoint: imds.URL,
+		},
+	}}
+	p, err := New(cfg, vc)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tok, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if tok != "s.azure" {
+		t.Fatalf("want s.azure, got %q", tok)
+	}
+	want := map[string]string{
+		"role":                "backup",
+		"jwt":                 "aad-token",
+		"subscription_id":     "sub",
+		"resource_group_name": "rg",
+		"vm_name":             "vm-1",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("login payload %s: want %q, got %q (payload=%v)", k, v, got[k], got)
+		}
+	}
+	if _, ok := got["vmss_name"]; ok {
+		t.Fatalf("vmss_name must be omitted for a plain VM, payload=%v", got)
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// certProvider implements Vault auth using the TLS certificate method.
+// The client certificate itself is presented by the shared transport (see vault.Options).
+type certProvider struct {
+	cfg config.AuthConfig
+	vc  *vault.Client
+	ro  retry.Options
+}
+
+// newCertProvider validates configuration and returns a provider.
+// The client key pair is mandatory; the role name is optional.
+func newCertProvider(cfg config.Config, vc *vault.Client) (*certProvider, error) {
+	if strings.TrimSpace(cfg.Auth.ClientCert) == "" || strings.TrimSpace(cfg.Auth.ClientKey) == "" {
+		return nil, errors.New("cert auth requires a client certificate and key")
+	}
+	if vc == nil {
+		return nil, errors.New("cert auth requires a vault client")
+	}
+	return &certProvider{cfg: cfg.Auth, vc: vc.WithNamespace(cfg.Auth.AuthNamespace), ro: cfg.RetryOptions()}, nil
+}
+
+// Acquire logs in with the TLS client certificate and returns a Vault client token.
+func (p *certProvider) Acquire(ctx context.Context) (string, error) {
+	// Without a name Vault tries every certificate role on the mount.
+	body := map[string]string{}
+	if p.cfg.Role != "" {
+		body["name"] = p.cfg.Role
+	}
+	return login(ctx, p.vc, p.ro, "cert", p.cfg.Mount, p.cfg.Role, func(context.Context) (any, error) {
+		return body, nil
+	})
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+)
+
+// helperTimeout bounds a token helper invocation.
+const helperTimeout = 10 * time.Second
+
+// fileProvider reads a token written by someone else: a Vault Agent auto-auth sink,
+// ~/.vault-token, or an external token helper ("<helper> get").
+// The token is read on every Acquire so a rotated sink is picked up.
+type fileProvider struct {
+	path   string
+	helper string
+}
+
+// newFileProvider validates configuration and returns a provider.
+// A token file or a token helper is mandatory.
+func newFileProvider(cfg config.Config) (*fileProvider, error) {
+	if strings.TrimSpace(cfg.Auth.TokenPath) == "" && strings.TrimSpace(cfg.Auth.TokenHelper) == "" {
+		return nil, errors.New("file auth requires token file or token helper")
+	}
+	return &fileProvider{path: cfg.Auth.TokenPath, helper: cfg.Auth.TokenHelper}, nil
+}
+
+// Acquire reads the token from the helper (preferred) or the file.
+func (p *fileProvider) Acquire(ctx context.Context) (string, error) {
+	var (
+		raw    []byte
+		err    error
+		source string
+	)
+	if p.helper != "" {
+		source = "helper"
+		raw, err = runTokenHelper(ctx, p.helper)
+	} else {
+		source = "file"
+		raw, err = os.ReadFile(p.path)
+		if err != nil {
+			err = fmt.Errorf("read token file: %w", err)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Never log the token content.
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		log.Debug().
+			Str("action", "auth_acquire").
+			Str("method", "file").
+			Str("source", source).
+			Msg("missing token")
+		return "", ErrNoToken
+	}
+	log.Debug().
+		Str("action", "auth_acquire").
+		Str("method", "file").
+		Str("source", source).
+		Msg("token acquired")
+	return token, nil
+}
+
+// runTokenHelper executes "<helper> get" and returns its stdout (Vault token helper protocol).
+func runTokenHelper(ctx context.Context, helper string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, helperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, helper, "get") //nolint:gosec // helper path is operator configuration
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("token helper %q: %w (%s)", helper, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
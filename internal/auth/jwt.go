@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// jwtProvider implements Vault auth for JWT-based methods: jwt/oidc and kubernetes.
+// Both exchange a signed JWT (+ role) for a client token on auth/<mount>/login.
+type jwtProvider struct {
+	method string
+	mount  string
+	role   string
+	path   string // JWT file, read on every Acquire (projected tokens rotate)
+	jwt    string // literal JWT, used when path is empty
+	extra  map[string]string
+	vc     *vault.Client
+	ro     retry.Options
+}
+
+// newJWTProvider validates configuration and returns a provider for the jwt method.
+// Either a JWT file or a literal JWT is mandatory; the role may be left to the mount's default_role.
+func newJWTProvider(cfg config.Config, vc *vault.Client) (*jwtProvider, error) {
+	if strings.TrimSpace(cfg.Auth.JWTPath) == "" && strings.TrimSpace(cfg.Auth.JWT) == "" {
+		return nil, errors.New("jwt auth requires jwt path or jwt")
+	}
+	if vc == nil {
+		return nil, errors.New("jwt auth requires a vault client")
+	}
+	return &jwtProvider{
+		method: "jwt",
+		mount:  cfg.Auth.Mount,
+		role:   cfg.Auth.Role,
+		path:   cfg.Auth.JWTPath,
+		jwt:    cfg.Auth.JWT,
+		vc:     vc.WithNamespace(cfg.Auth.AuthNamespace),
+		ro:     cfg.RetryOptions(),
+	}, nil
+}
+
+// newKubernetesProvider validates configuration and returns a provider for the kubernetes method.
+// Role and JWT path are mandatory.
+func newKubernetesProvider(cfg config.Config, vc *vault.Client) (*jwtProvider, error) {
+	if strings.TrimSpace(cfg.Auth.Role) == "" {
+		return nil, errors.New("kubernetes auth requires role")
+	}
+	if strings.TrimSpace(cfg.Auth.JWTPath) == "" {
+		return nil, errors.New("kubernetes auth requires jwt path")
+	}
+	if vc == nil {
+		return nil, errors.New("kubernetes auth requires a vault client")
+	}
+	p := &jwtProvider{
+		method: "kubernetes",
+		mount:  cfg.Auth.Mount,
+		role:   cfg.Auth.Role,
+		path:   cfg.Auth.JWTPath,
+		vc:     vc.WithNamespace(cfg.Auth.AuthNamespace),
+		ro:     cfg.RetryOptions(),
+	}
+	if cfg.Auth.Audience != "" {
+		p.extra = map[string]string{"audience": cfg.Auth.Audience}
+	}
+	return p, nil
+}
+
+// Acquire exchanges the JWT for a Vault client token.
+// The JWT is re-read before every attempt: projected tokens rotate.
+func (p *jwtProvider) Acquire(ctx context.Context) (string, error) {
+	return login(ctx, p.vc, p.ro, p.method, p.mount, p.role, func(context.Context) (any, error) {
+		jwt, err := p.readJWT()
+		if err != nil {
+			return nil, err
+		}
+
+		// Build login request payload.
+		body := map[string]string{"jwt": jwt}
+		if p.role != "" {
+			body["role"] = p.role
+		}
+		for k, v := range p.extra {
+			body[k] = v
+		}
+		return body, nil
+	})
+}
+
+// readJWT returns the JWT from file (preferred) or the literal value.
+func (p *jwtProvider) readJWT() (string, error) {
+	jwt := p.jwt
+	if p.path != "" {
+		data, err := os.ReadFile(p.path)
+		if err != nil {
+			return "", fmt.Errorf("read jwt: %w", err)
+		}
+		jwt = string(data)
+	}
+	jwt = strings.TrimSpace(jwt)
+	if jwt == "" {
+		return "", fmt.Errorf("%s auth: empty jwt", p.method)
+	}
+	return jwt, nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// jwtLogins serves auth/jwt/login and records every payload; onLogin can alter the answer.
+func jwtLogins(t *testing.T, onLogin func(n int) int) (*vault.Client, *[]map[string]string) {
+	t.Helper()
+	var payloads []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/jwt/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		payloads = append(payloads, in)
+		if code := onLogin(len(payloads)); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.jwt"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vc, &payloads
+}
+
+// 1) The role is sent when configured; without it the mount's default_role applies
+func TestJWTProvider_Role(t *testing.T) {
+	for _, role := range []string{"backup", ""} {
+		vc, payloads := jwtLogins(t, func(int) int { return http.StatusOK })
+		cfg := config.Config{RetryMaxAttempts: 1, Auth: config.AuthConfig{Method: "jwt", Mount: "jwt", Role: role, JWT: "header.payload.sig"}}
+		p, err := newJWTProvider(cfg, vc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+		got := (*payloads)[0]
+		if got["jwt"] != "header.payload.sig" {
+			t.Fatalf("jwt not sent: %v", got)
+		}
+		if r, ok := got["role"]; r != role || ok != (role != "") {
+			t.Fatalf("role %q: unexpected payload %v", role, got)
+		}
+	}
+}
+
+// 2) The JWT file is re-read on every login attempt, so a rotated token is picked up
+func TestJWTProvider_RereadsFileEachAttempt(t *testing.T) {
+	jwtPath := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(jwtPath, []byte("jwt-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vc, payloads := jwtLogins(t, func(n int) int {
+		if n > 1 {
+			return http.StatusOK
+		}
+		// The first attempt fails transiently while the projected token rotates.
+		if err := os.WriteFile(jwtPath, []byte("jwt-2\n"), 0o600); err != nil {
+			t.Error(err)
+		}
+		return http.StatusServiceUnavailable
+	})
+	cfg := config.Config{
+		RetryMaxAttempts:  2,
+		RetryInitialDelay: time.Millisecond,
+		RetryMaxDelay:     time.Millisecond,
+		RetryMultiplier:   1,
+		Auth:              config.AuthConfig{Method: "jwt", Mount: "jwt", JWTPath: jwtPath},
+	}
+	p, err := newJWTProvider(cfg, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if len(*payloads) != 2 || (*payloads)[0]["jwt"] != "jwt-1" || (*payloads)[1]["jwt"] != "jwt-2" {
+		t.Fatalf("want jwt-1 then jwt-2, got %v", *payloads)
+	}
+}
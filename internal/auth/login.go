@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// login posts the payload to auth/<mount>/login through vc (with retries) and logs the outcome.
+// Every login-based method goes through here so request and error handling stay identical.
+// payload runs before each attempt so credentials are re-read.
+func login(ctx context.Context, vc *vault.Client, ro retry.Options, method, mount, role string, payload vault.PayloadFunc) (string, error) {
+	token, err := vc.Login(ctx, mount, payload, ro)
+	if err != nil {
+		return "", err
+	}
+
+	// Never log the token content.
+	log.Info().
+		Str("action", "auth_acquire").
+		Str("method", method).
+		Str("mount", mount).
+		Str("role", role).
+		Str("namespace", vc.Namespace()).
+		Msg(method + " login OK")
+
+	return token, nil
+}
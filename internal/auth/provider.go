@@ -8,21 +8,23 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
 )
 
 var (
 	ErrNoToken = errors.New("no token available for vault auth")
 )
 
-// Provider abstracts how we acquire a Vault token (no renew here).
+// Provider abstracts how we acquire a Vault token (renewal lives in Session).
 type Provider interface {
 	Acquire(ctx context.Context) (string, error)
 }
 
 // New selects the provider based on cfg.Auth.Method.
+// Login-based providers send their requests through vc.
 // NOTE: This package never initializes logging; main() does via logx.InitFromEnv().
-func New(cfg config.Config) (Provider, error) {
-	method := strings.ToLower(strings.TrimSpace(cfg.Auth.Method))
+func New(cfg config.Config, vc *vault.Client) (Provider, error) {
+	method := normalizeMethod(cfg.Auth.Method)
 	switch method {
 	case "token":
 		log.Debug().
@@ -31,16 +33,67 @@ func New(cfg config.Config) (Provider, error) {
 			Msg("auth provider selected")
 		return &tokenProvider{token: strings.TrimSpace(cfg.Auth.Token)}, nil
 
+	case "file":
+		log.Debug().
+			Str("action", "auth_new").
+			Str("method", "file").
+			Str("path", cfg.Auth.TokenPath).
+			Str("helper", cfg.Auth.TokenHelper).
+			Msg("auth provider selected")
+		return newFileProvider(cfg)
+
 	case "kubernetes":
 		log.Debug().
 			Str("action", "auth_new").
 			Str("method", "kubernetes").
 			Str("mount", cfg.Auth.Mount).
 			Str("role", cfg.Auth.Role).
-			Msg("auth provider selected (not implemented yet)")
-		return newKubernetesProvider(cfg)
+			Msg("auth provider selected")
+		return newKubernetesProvider(cfg, vc)
+
+	case "cert":
+		log.Debug().
+			Str("action", "auth_new").
+			Str("method", "cert").
+			Str("mount", cfg.Auth.Mount).
+			Str("role", cfg.Auth.Role).
+			Msg("auth provider selected")
+		return newCertProvider(cfg, vc)
+
+	case "approle":
+		log.Debug().
+			Str("action", "auth_new").
+			Str("method", "approle").
+			Str("mount", cfg.Auth.Mount).
+			Bool("wrapped", cfg.Auth.SecretWrapped).
+			Msg("auth provider selected")
+		return newApproleProvider(cfg, vc)
+
+	case "jwt":
+		log.Debug().
+			Str("action", "auth_new").
+			Str("method", "jwt").
+			Str("mount", cfg.Auth.Mount).
+			Str("role", cfg.Auth.Role).
+			Msg("auth provider selected")
+		return newJWTProvider(cfg, vc)
+
+	case "azure":
+		log.Debug().
+			Str("action", "auth_new").
+			Str("method", "azure").
+			Str("mount", cfg.Auth.Mount).
+			Str("role", cfg.Auth.Role).
+			Bool("workload_identity", cfg.Auth.Azure.FederatedTokenFile != "").
+			Msg("auth provider selected")
+		return newAzureProvider(cfg, vc)
 
 	default:
 		return nil, errors.New("unsupported auth method: " + method)
 	}
 }
+
+// normalizeMethod lowercases and trims an auth method name.
+func normalizeMethod(m string) string {
+	return strings.ToLower(strings.TrimSpace(m))
+}
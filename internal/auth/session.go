@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// Renewal tuning: renew at 2/3 of the TTL, retry failed renewals sooner.
+const (
+	renewFraction = 2.0 / 3.0
+	renewRetryMin = 5 * time.Second
+	revokeTimeout = 10 * time.Second
+)
+
+// Session holds the Vault token for one backup/restore run.
+// It keeps operator-created tokens alive during long transfers and revokes them on Close.
+type Session struct {
+	vc       *vault.Client
+	provider Provider
+	method   string
+	owned    bool
+	info     vault.TokenInfo
+
+	mu    sync.Mutex
+	token string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Start acquires a token, looks it up and renews it in the background when possible.
+// Call Close when the run is over.
+func Start(ctx context.Context, cfg config.Config, vc *vault.Client) (*Session, error) {
+	p, err := New(cfg, vc)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	method := normalizeMethod(cfg.Auth.Method)
+	s := &Session{
+		vc:       vc.WithNamespace(cfg.Auth.AuthNamespace),
+		provider: p,
+		method:   method,
+		token:    token,
+		owned:    ownsToken(method),
+	}
+
+	info, err := s.vc.LookupSelf(ctx, token)
+	if err != nil {
+		// Not fatal: the snapshot call will fail anyway if the token is unusable.
+		log.Warn().Err(err).Str("action", "auth_lookup").Str("method", method).
+			Msg("token lookup failed; renewal disabled")
+		return s, nil
+	}
+	s.info = info
+	log.Info().
+		Str("action", "auth_lookup").
+		Str("method", method).
+		Str("accessor", info.Accessor).
+		Strs("policies", info.Policies).
+		Dur("ttl", info.TTL).
+		Bool("renewable", info.Renewable).
+		Bool("owned", s.owned).
+		Msg("token lookup OK")
+
+	if info.Renewable && info.TTL > 0 {
+		rctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		s.cancel = cancel
+		s.done = make(chan struct{})
+		go s.renewLoop(rctx)
+	}
+	return s, nil
+}
+
+// Token returns the current Vault token.
+func (s *Session) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// Do runs fn with the current token. When the token comes from a file or helper
+// (Vault Agent sink), a 403 triggers one re-read and, if the token changed, a second attempt.
+func (s *Session) Do(ctx context.Context, fn func(token string) error) error {
+	err := fn(s.Token())
+	if err == nil || !rereadable(s.method) || !vault.IsStatus(err, http.StatusForbidden) {
+		return err
+	}
+
+	token, rerr := s.provider.Acquire(ctx)
+	if rerr != nil {
+		log.Warn().Err(rerr).Str("action", "auth_reread").Str("method", s.method).Msg("token re-read failed")
+		return err
+	}
+	s.mu.Lock()
+	changed := token != s.token
+	s.token = token
+	s.mu.Unlock()
+	if !changed {
+		return err
+	}
+
+	log.Info().Str("action", "auth_reread").Str("method", s.method).Msg("token changed after 403; retrying")
+	return fn(token)
+}
+
+// Close stops background renewal and revokes the token if the operator created it.
+// A user-supplied token (VAULT_TOKEN) is never revoked.
+func (s *Session) Close() {
+	if s == nil {
+		return
+	}
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	if !s.owned {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), revokeTimeout)
+	defer cancel()
+	if err := s.vc.RevokeSelf(ctx, s.Token()); err != nil {
+		log.Warn().Err(err).Str("action", "auth_revoke").Str("method", s.method).
+			Str("accessor", s.info.Accessor).Msg("token revoke failed")
+		return
+	}
+	log.Info().Str("action", "auth_revoke").Str("method", s.method).
+		Str("accessor", s.info.Accessor).Msg("token revoked")
+}
+
+// renewLoop renews the token at a fraction of its TTL until ctx is canceled, the token
+// expires, or max_ttl stops renewals from extending it.
+func (s *Session) renewLoop(ctx context.Context) {
+	defer close(s.done)
+
+	increment := s.info.TTL
+	ttl := s.info.TTL
+	wait := time.Duration(float64(ttl) * renewFraction)
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		newTTL, err := s.vc.RenewSelf(ctx, s.Token(), increment)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Retry before the remaining TTL runs out.
+			ttl -= wait
+			if ttl <= 0 {
+				log.Error().Err(err).Str("action", "auth_renew").Str("method", s.method).
+					Msg("token expired; renewal stopped")
+				return
+			}
+			wait = max(ttl/3, renewRetryMin)
+			log.Warn().Err(err).Str("action", "auth_renew").Str("method", s.method).
+				Dur("remaining_ttl", ttl).Dur("retry_in", wait).Msg("token renew failed")
+			continue
+		}
+		if newTTL < increment {
+			// Max TTL reached: Vault grants less than asked for; later renewals cannot extend it.
+			log.Debug().Str("action", "auth_renew").Str("method", s.method).
+				Dur("ttl", newTTL).Msg("token renewed up to max_ttl; renewal stopped")
+			return
+		}
+		log.Debug().Str("action", "auth_renew").Str("method", s.method).Dur("ttl", newTTL).Msg("token renewed")
+		ttl = newTTL
+		wait = time.Duration(float64(ttl) * renewFraction)
+		if wait <= 0 {
+			return
+		}
+	}
+}
+
+// ownsToken reports whether tokens obtained by method were created by the operator itself.
+// Tokens from VAULT_TOKEN or a Vault Agent sink belong to someone else.
+func ownsToken(method string) bool {
+	return method != "token" && method != "file"
+}
+
+// rereadable reports whether the token source may hold a newer token after a 403.
+func rereadable(method string) bool {
+	return method == "file"
+}
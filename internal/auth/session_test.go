@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// fakeVault counts token lifecycle calls.
+type fakeVault struct {
+	mu    sync.Mutex
+	calls map[string]int
+	ttl   int
+	// renewTTL is the lease granted on renew-self (ttl when 0), below ttl once max_ttl caps it.
+	renewTTL int
+}
+
+func (f *fakeVault) handler(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[r.URL.Path]++
+	switch r.URL.Path {
+	case "/v1/auth/token/lookup-self":
+		_, _ = w.Write([]byte(`{"data":{"accessor":"acc","ttl":` + strconv.Itoa(f.ttl) + `,"renewable":true}}`))
+	case "/v1/auth/token/renew-self":
+		granted := f.ttl
+		if f.renewTTL > 0 {
+			granted = f.renewTTL
+		}
+		_, _ = w.Write([]byte(`{"auth":{"lease_duration":` + strconv.Itoa(granted) + `}}`))
+	case "/v1/auth/token/revoke-self":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.login"}}`))
+	}
+}
+
+func (f *fakeVault) count(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[path]
+}
+
+func newFakeVault(t *testing.T, ttl int) (*fakeVault, *vault.Client) {
+	t.Helper()
+	f := &fakeVault{calls: map[string]int{}, ttl: ttl}
+	srv := httptest.NewServer(http.HandlerFunc(f.handler))
+	t.Cleanup(srv.Close)
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, vc
+}
+
+// 1) Login-based tokens are renewed in the background and revoked on Close
+func TestSession_RenewsAndRevokesOwnedToken(t *testing.T) {
+	f, vc := newFakeVault(t, 1) // 1s TTL -> renewal after ~666ms
+	jwtPath := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(jwtPath, []byte("header.payload.sig"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Config{Auth: config.AuthConfig{Method: "jwt", Mount: "jwt", JWTPath: jwtPath}}
+	s, err := Start(context.Background(), cfg, vc)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if s.Token() != "s.login" {
+		t.Fatalf("want s.login, got %q", s.Token())
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for f.count("/v1/auth/token/renew-self") == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.Close()
+
+	if n := f.count("/v1/auth/token/renew-self"); n == 0 {
+		t.Fatal("expected at least one renew-self call")
+	}
+	if n := f.count("/v1/auth/token/revoke-self"); n != 1 {
+		t.Fatalf("want 1 revoke-self call, got %d", n)
+	}
+}
+
+// 2) A user-supplied VAULT_TOKEN is never revoked
+func TestSession_NeverRevokesUserToken(t *testing.T) {
+	f, vc := newFakeVault(t, 9)
+
+	cfg := config.Config{Auth: config.AuthConfig{Method: "token", Token: "s.user"}}
+	s, err := Start(context.Background(), cfg, vc)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	s.Close()
+
+	if n := f.count("/v1/auth/token/lookup-self"); n != 1 {
+		t.Fatalf("want 1 lookup-self call, got %d", n)
+	}
+	if n := f.count("/v1/auth/token/revoke-self"); n != 0 {
+		t.Fatalf("user token must not be revoked, got %d revoke calls", n)
+	}
+}
+
+// 3) A Vault Agent sink token is re-read once after a 403
+func TestSession_RereadsTokenFileOn403(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.new" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"accessor":"acc"}}`))
+	}))
+	defer srv.Close()
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := filepath.Join(t.TempDir(), "sink")
+	if err := os.WriteFile(sink, []byte("s.old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{Auth: config.AuthConfig{Method: "file", TokenPath: sink}}
+	s, err := Start(context.Background(), cfg, vc)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	// Vault Agent rotates the sink.
+	if err := os.WriteFile(sink, []byte("s.new\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var used []string
+	err = s.Do(context.Background(), func(token string) error {
+		used = append(used, token)
+		_, err := vc.LookupSelf(context.Background(), token)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if len(used) != 2 || used[0] != "s.old" || used[1] != "s.new" {
+		t.Fatalf("want [s.old s.new], got %v", used)
+	}
+}
+
+// 4) Renewal stops once max_ttl caps the granted TTL
+func TestSession_StopsRenewingAtMaxTTL(t *testing.T) {
+	f, vc := newFakeVault(t, 2) // renewal after ~1.3s
+	f.renewTTL = 1              // less than requested: max_ttl reached
+
+	cfg := config.Config{Auth: config.AuthConfig{Method: "token", Token: "s.user"}}
+	s, err := Start(context.Background(), cfg, vc)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	select {
+	case <-s.done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("renewal loop still running after a capped renewal")
+	}
+	if n := f.count("/v1/auth/token/renew-self"); n != 1 {
+		t.Fatalf("want 1 renew-self call, got %d", n)
+	}
+}
@@ -0,0 +1,168 @@
+// Package autosnapshot manages Vault Enterprise automated snapshot configurations
+// (sys/storage/raft/snapshot-auto) that write to the operator's storage container.
+package autosnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// ErrUsage is returned for an unknown subcommand.
+var ErrUsage = errors.New("usage: operator auto-snapshot list|get|apply|delete|status [name]")
+
+// Run executes an auto-snapshot subcommand and writes its result as JSON to out.
+// name defaults to AUTO_SNAPSHOT_NAME; it is ignored by "list".
+func Run(ctx context.Context, cfg config.Config, sub, name string, out io.Writer) error {
+	sub = strings.ToLower(strings.TrimSpace(sub))
+	switch sub {
+	case "list", "get", "apply", "delete", "status":
+	default:
+		return ErrUsage
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = cfg.AutoSnapshot.Name
+	}
+
+	var desired vault.AutoSnapshotConfig
+	if sub == "apply" {
+		var err error
+		if desired, err = buildConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	vc, err := vault.NewClient(cfg.VaultOptions())
+	if err != nil {
+		return fmt.Errorf("vault client: %w", err)
+	}
+	if err := vc.SelectNode(ctx); err != nil {
+		return err
+	}
+	sess, err := auth.Start(ctx, cfg, vc)
+	if err != nil {
+		log.Error().Err(err).Str("action", "auto_snapshot_auth").Str("method", cfg.Auth.Method).Msg("vault auth failed")
+		return err
+	}
+	defer sess.Close()
+
+	var result any
+	err = sess.Do(ctx, func(token string) (err error) {
+		switch sub {
+		case "list":
+			var names []string
+			names, err = vc.ListAutoSnapshots(ctx, token)
+			result = append([]string{}, names...)
+		case "get":
+			var c vault.AutoSnapshotConfig
+			c, err = vc.ReadAutoSnapshot(ctx, token, name)
+			result = c
+		case "status":
+			result, err = vc.AutoSnapshotStatusOf(ctx, token, name)
+		case "apply":
+			if err = vc.WriteAutoSnapshot(ctx, token, name, desired); err == nil {
+				result, err = vc.ReadAutoSnapshot(ctx, token, name)
+			}
+		case "delete":
+			err = vc.DeleteAutoSnapshot(ctx, token, name)
+		}
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Str("action", "auto_snapshot").Str("command", sub).Str("name", name).Msg("auto-snapshot command failed")
+		return err
+	}
+
+	ev := log.Info().Str("action", "auto_snapshot").Str("command", sub)
+	if sub != "list" {
+		ev = ev.Str("name", name)
+	}
+	if sub == "apply" {
+		ev = ev.
+			Str("container", desired.AzureContainerName).
+			Str("path_prefix", desired.PathPrefix).
+			Int64("interval_s", desired.Interval).
+			Int("retain", desired.Retain).
+			Str("azure_auth_mode", desired.AzureAuthMode)
+	}
+	ev.Msg("auto-snapshot OK")
+
+	if result == nil {
+		return nil
+	}
+	// Read-back configs may carry storage credentials; stdout often ends up in CI logs.
+	result, err = redact(result)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// redactedValue replaces credential values in printed output.
+const redactedValue = "<redacted>"
+
+// redact returns v as generic JSON with credential fields (*_key, *_secret, *_token,
+// credentials*) masked at any depth. Empty values are kept so "not set" stays visible.
+func redact(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return redactValue(doc), nil
+}
+
+func redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if isCredential(k) && val != nil && val != "" {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = redactValue(val)
+		}
+	case []any:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+	}
+	return v
+}
+
+// isCredential reports whether a config field name holds secret material.
+func isCredential(name string) bool {
+	n := strings.ToLower(name)
+	return strings.HasSuffix(n, "_key") || strings.HasSuffix(n, "_secret") ||
+		strings.HasSuffix(n, "_token") || strings.HasPrefix(n, "credentials")
+}
+
+// buildConfig derives the automated snapshot configuration from the operator's Azure settings.
+func buildConfig(cfg config.Config) (vault.AutoSnapshotConfig, error) {
+	if cfg.Provider != "azure" {
+		return vault.AutoSnapshotConfig{}, fmt.Errorf("auto-snapshot: provider %q not supported (azure only)", cfg.Provider)
+	}
+	c := cfg.AutoSnapshotOptions()
+	if c.Interval <= 0 {
+		return c, errors.New("auto-snapshot: AUTO_SNAPSHOT_INTERVAL must be positive")
+	}
+	if c.AzureAuthMode == "shared" && c.AzureAccountKey == "" {
+		return c, errors.New("auto-snapshot: azure_auth_mode=shared requires AZURE_STORAGE_KEY (Vault cannot use a SAS token)")
+	}
+	return c, nil
+}
@@ -0,0 +1,53 @@
+package autosnapshot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+func TestRedact_MasksCredentials(t *testing.T) {
+	cfg := vault.AutoSnapshotConfig{
+		Interval:           3600,
+		PathPrefix:         "vault/snapshots",
+		StorageType:        "azure-blob",
+		AzureAccountName:   "backups",
+		AzureContainerName: "snapshots",
+		AzureAuthMode:      "shared",
+		AzureAccountKey:    "c2VjcmV0LWFjY291bnQta2V5",
+	}
+	out, err := redact(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := out.(map[string]any)
+	if doc["azure_account_key"] != redactedValue {
+		t.Fatalf("azure_account_key not masked: %v", doc["azure_account_key"])
+	}
+	if doc["azure_account_name"] != "backups" || doc["path_prefix"] != "vault/snapshots" {
+		t.Fatalf("non-credential fields changed: %v", doc)
+	}
+
+	// Fields of other storage types, at any depth; empty values stay visible.
+	raw := map[string]any{
+		"aws_secret_access_key":      "AKIA-secret",
+		"aws_session_token":          "tok",
+		"google_service_account_key": "",
+		"nested":                     []any{map[string]any{"credentials_json": "{}", "client_secret": "s"}},
+	}
+	out, err = redact(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(out)
+	for _, leaked := range []string{"AKIA-secret", `"tok"`, `"{}"`, `"s"`} {
+		if strings.Contains(string(data), leaked) {
+			t.Fatalf("%s leaked: %s", leaked, data)
+		}
+	}
+	if !strings.Contains(string(data), `"google_service_account_key":""`) {
+		t.Fatalf("empty credential should stay empty: %s", data)
+	}
+}
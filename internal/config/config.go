@@ -3,11 +3,14 @@ package config
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
 )
 
 type Config struct {
@@ -19,10 +22,25 @@ type Config struct {
 	BackupSource          string
 	BackupTarget          string
 	BackupTimestampFormat string
+	BackupHealthPolicy    string   // "fail" (default) or "skip" when the cluster is not ready
+	BackupQuorumPolicy    string   // "warn" (default) or "fail" on lost quorum / unhealthy voters
+	BackupMinSize         int64    // smallest snapshot (bytes) accepted before upload
+	Digests               []string // digests computed while snapshots stream: sha256, sha512
 	RestoreSource         string
 	RestoreTarget         string
+	RestoreVerify         VerifyConfig
+	RestoreAllowSecondary bool   // restore onto a DR/performance replication secondary
+	RestoreSafetySnapshot bool   // snapshot the target before restoring (default false)
+	RestoreRollbackPrefix string // provider prefix for safety snapshots (default rollback)
+	RestoreConfirmCluster string // target cluster_id confirming a forced (cross-cluster) restore
+	RestoreUnseal         bool   // unseal every node after the restore (keys from UnsealKeyFiles)
 
-	Azure AzureConfig
+	UnsealKeyFiles []string // files holding unseal key shares, one per line ("-" is stdin)
+
+	RaftJoin RaftJoinConfig
+
+	Azure        AzureConfig
+	AutoSnapshot AutoSnapshotConfig
 
 	RetryMaxAttempts  int
 	RetryInitialDelay time.Duration
@@ -31,27 +49,92 @@ type Config struct {
 	RetryEnableJitter bool
 }
 
+// VerifyConfig controls the optional post-restore verification.
+type VerifyConfig struct {
+	Enabled       bool          // RESTORE_VERIFY
+	HealthTimeout time.Duration // unsealed with an active leader (default 2m)
+	IndexTimeout  time.Duration // raft applied index reaches the snapshot index (default 2m)
+	CanaryPath    string        // optional secret path read after re-login
+	CanaryTimeout time.Duration // default 30s
+	Interval      time.Duration // poll interval (default 2s)
+}
+
 type AzureConfig struct {
 	Account   string
 	Container string
 	SASToken  string
+	// AccountKey is the storage account shared key (AZURE_STORAGE_KEY); only used by
+	// Vault automated snapshots with azure_auth_mode=shared.
+	AccountKey string
 
 	ClientID     string
 	ClientSecret string
 	TenantID     string
 }
 
+// AutoSnapshotConfig holds the Vault Enterprise automated snapshot settings
+// managed by "operator auto-snapshot". Storage is the operator's Azure container.
+type AutoSnapshotConfig struct {
+	Name       string        // default configuration name (default vault-raft-backup)
+	Interval   time.Duration // default 1h
+	Retain     int           // snapshots kept by Vault (default 24)
+	PathPrefix string        // container directory (default BACKUP_TARGET or vault/snapshots)
+	FilePrefix string        // default vault-snapshot
+	AuthMode   string        // "shared" (AZURE_STORAGE_KEY) or "managed"; default from AZURE_STORAGE_KEY
+	Endpoint   string        // optional blob endpoint override
+}
+
+// RaftJoinConfig holds the "operator raft join" settings.
+type RaftJoinConfig struct {
+	LeaderAddr   string // leader API address (default: leader of VAULT_ADDR)
+	LeaderCACert string // PEM file sent as leader_ca_cert
+	// LeaderClientCert/Key are PEM files sent as leader_client_cert/key when the
+	// leader's cluster listener requires mTLS.
+	LeaderClientCert string
+	LeaderClientKey  string
+	NonVoter         bool // join as a non-voter (Enterprise)
+	Retry            bool // keep retrying the join in the background
+}
+
 type AuthConfig struct {
-	Method     string // "token" or "kubernetes"
-	Token      string // only if Method == token
-	Mount      string // default "kubernetes"
-	Role       string // required if Method == kubernetes
-	JWTPath    string // default /var/run/secrets/kubernetes.io/serviceaccount/token
-	Audience   string // optional, for projected SA tokens
-	Namespace  string // optional, Vault Enterprise namespace
-	CACert     string // optional
-	CAPath     string // optional
-	SkipVerify bool   // optional
+	Method        string // "token", "file", "kubernetes", "cert", "approle", "jwt" or "azure"
+	Token         string // only if Method == token
+	TokenPath     string // token file if Method == file (default ~/.vault-token)
+	TokenHelper   string // token helper executable if Method == file (run as "<helper> get")
+	Mount         string // default: method name
+	Role          string // required if Method == kubernetes/azure, optional for cert/jwt
+	JWTPath       string // default /var/run/secrets/kubernetes.io/serviceaccount/token (kubernetes)
+	JWT           string // literal JWT for Method == jwt when no JWTPath is given
+	Audience      string // optional, for projected SA tokens
+	RoleID        string // required if Method == approle
+	SecretID      string // approle secret-id from env (or SecretIDPath)
+	SecretIDPath  string // approle secret-id file
+	SecretWrapped bool   // secret-id is a response-wrapping token to unwrap first
+	Namespace     string // optional, Vault Enterprise namespace
+	AuthNamespace string // optional, namespace of the auth mount (default: Namespace)
+	CACert        string // optional
+	CAPath        string // optional
+	TLSServerName string // optional, overrides SNI / verification host name
+	ClientCert    string // optional, PEM client certificate for mTLS / cert auth
+	ClientKey     string // optional, PEM private key matching ClientCert
+	SkipVerify    bool   // optional
+
+	Azure AzureAuthConfig // only if Method == azure
+}
+
+// AzureAuthConfig holds the Azure auth method settings.
+// ClientID/TenantID are shared with blob storage so one identity covers both.
+type AzureAuthConfig struct {
+	Resource           string // default https://management.azure.com/
+	ClientID           string // user-assigned identity / workload identity client ID
+	TenantID           string // required with FederatedTokenFile
+	FederatedTokenFile string // AKS workload identity projected token
+	IMDSEndpoint       string // default http://169.254.169.254
+	SubscriptionID     string // optional, read from IMDS when empty
+	ResourceGroup      string // optional, read from IMDS when empty
+	VMName             string // optional
+	VMSSName           string // optional
+	ResourceID         string // optional
 }
 
 // Load reads config from environment variables, applies defaults and validates.
@@ -74,8 +157,29 @@ func Load() (Config, error) {
 		BackupSource:          getEnvWithDefault("BACKUP_SOURCE", ""),
 		BackupTarget:          getEnvWithDefault("BACKUP_TARGET", ""),
 		BackupTimestampFormat: getEnvWithDefault("BACKUP_TIMESTAMP_FORMAT", ""),
+		BackupHealthPolicy:    strings.ToLower(strings.TrimSpace(getEnvWithDefault("BACKUP_HEALTH_POLICY", "fail"))),
+		BackupQuorumPolicy:    strings.ToLower(strings.TrimSpace(getEnvWithDefault("BACKUP_QUORUM_POLICY", "warn"))),
+		BackupMinSize:         int64(parseEnvInt("BACKUP_MIN_SIZE", 1024)),
+		Digests:               splitList(strings.ToLower(getEnvWithDefault("SNAPSHOT_DIGESTS", "sha256"))),
 		RestoreSource:         getEnvWithDefault("RESTORE_SOURCE", ""),
 		RestoreTarget:         getEnvWithDefault("RESTORE_TARGET", ""),
+		RestoreVerify:         loadVerifyConfig(),
+		RestoreAllowSecondary: parseEnvBool("RESTORE_ALLOW_SECONDARY", false),
+		RestoreSafetySnapshot: parseEnvBool("RESTORE_SAFETY_SNAPSHOT", false),
+		RestoreRollbackPrefix: getEnvWithDefault("RESTORE_ROLLBACK_PREFIX", "rollback"),
+		RestoreConfirmCluster: strings.TrimSpace(getEnvWithDefault("RESTORE_CONFIRM_CLUSTER_ID", "")),
+		RestoreUnseal:         parseEnvBool("RESTORE_UNSEAL", false),
+
+		UnsealKeyFiles: splitList(getEnvWithDefault("UNSEAL_KEY_FILES", "")),
+
+		RaftJoin: RaftJoinConfig{
+			LeaderAddr:       strings.TrimRight(strings.TrimSpace(getEnvWithDefault("RAFT_JOIN_LEADER_ADDR", "")), "/"),
+			LeaderCACert:     strings.TrimSpace(getEnvWithDefault("RAFT_JOIN_LEADER_CACERT", "")),
+			LeaderClientCert: strings.TrimSpace(getEnvWithDefault("RAFT_JOIN_LEADER_CLIENT_CERT", "")),
+			LeaderClientKey:  strings.TrimSpace(getEnvWithDefault("RAFT_JOIN_LEADER_CLIENT_KEY", "")),
+			NonVoter:         parseEnvBool("RAFT_JOIN_NON_VOTER", false),
+			Retry:            parseEnvBool("RAFT_JOIN_RETRY", false),
+		},
 
 		Azure: loadAzureConfig(),
 
@@ -86,6 +190,8 @@ func Load() (Config, error) {
 		RetryEnableJitter: parseEnvBool("RETRY_JITTER", retry.Default.Jitter),
 	}
 
+	cfg.AutoSnapshot = loadAutoSnapshotConfig(cfg.BackupTarget, cfg.Azure.AccountKey)
+
 	if err := cfg.validate(); err != nil {
 		return Config{}, err
 	}
@@ -102,16 +208,24 @@ func loadAuthConfig() (AuthConfig, error) {
 	if method == "" {
 		method = detectAuthMethod(tokenEnv, defaultJWTPath)
 		if method == "" {
-			return AuthConfig{}, errors.New("no auth method configured: set VAULT_AUTH_METHOD=token with VAULT_TOKEN, or provide a readable VAULT_K8S_JWT_PATH for kubernetes")
+			return AuthConfig{}, errors.New("no auth method configured: set VAULT_AUTH_METHOD=token with VAULT_TOKEN, provide a readable VAULT_K8S_JWT_PATH for kubernetes, or VAULT_CLIENT_CERT/VAULT_CLIENT_KEY for cert")
 		}
 	}
 
+	namespace := strings.TrimSpace(getEnvWithDefault("VAULT_NAMESPACE", ""))
 	auth := AuthConfig{
-		Method:     method,
-		Namespace:  strings.TrimSpace(getEnvWithDefault("VAULT_NAMESPACE", "")),
-		CACert:     strings.TrimSpace(getEnvWithDefault("VAULT_CACERT", "")),
-		CAPath:     strings.TrimSpace(getEnvWithDefault("VAULT_CAPATH", "")),
-		SkipVerify: parseEnvBool("VAULT_SKIP_VERIFY", false),
+		Method:        method,
+		Namespace:     namespace,
+		AuthNamespace: strings.TrimSpace(getEnvWithDefault("VAULT_AUTH_NAMESPACE", namespace)),
+		CACert:        strings.TrimSpace(getEnvWithDefault("VAULT_CACERT", "")),
+		CAPath:        strings.TrimSpace(getEnvWithDefault("VAULT_CAPATH", "")),
+		TLSServerName: strings.TrimSpace(getEnvWithDefault("VAULT_TLS_SERVER_NAME", "")),
+		ClientCert:    strings.TrimSpace(getEnvWithDefault("VAULT_CLIENT_CERT", "")),
+		ClientKey:     strings.TrimSpace(getEnvWithDefault("VAULT_CLIENT_KEY", "")),
+		SkipVerify:    parseEnvBool("VAULT_SKIP_VERIFY", false),
+	}
+	if (auth.ClientCert == "") != (auth.ClientKey == "") {
+		return AuthConfig{}, errors.New("VAULT_CLIENT_CERT and VAULT_CLIENT_KEY must be set together")
 	}
 
 	if err := configureAuthMethod(&auth, method, tokenEnv, defaultJWTPath); err != nil {
@@ -126,9 +240,18 @@ func detectAuthMethod(tokenEnv, defaultJWTPath string) string {
 	if tokenEnv != "" {
 		return "token"
 	}
+	if isFileReadable(getEnvWithDefault("VAULT_TOKEN_FILE", "")) || getEnvWithDefault("VAULT_TOKEN_HELPER", "") != "" {
+		return "file"
+	}
+	if strings.TrimSpace(getEnvWithDefault("VAULT_APPROLE_ROLE_ID", "")) != "" {
+		return "approle"
+	}
 	if isFileReadable(getEnvWithDefault("VAULT_K8S_JWT_PATH", defaultJWTPath)) {
 		return "kubernetes"
 	}
+	if isFileReadable(getEnvWithDefault("VAULT_CLIENT_CERT", "")) {
+		return "cert"
+	}
 	return ""
 }
 
@@ -141,11 +264,23 @@ func configureAuthMethod(auth *AuthConfig, method, tokenEnv, defaultJWTPath stri
 			return errors.New("auth method token requires VAULT_TOKEN")
 		}
 
-	case "kubernetes":
-		auth.Mount = strings.TrimSpace(getEnvWithDefault("VAULT_AUTH_MOUNT", "kubernetes"))
-		if auth.Mount == "" {
-			auth.Mount = "kubernetes"
+	case "file":
+		auth.TokenHelper = strings.TrimSpace(getEnvWithDefault("VAULT_TOKEN_HELPER", ""))
+		auth.TokenPath = strings.TrimSpace(getEnvWithDefault("VAULT_TOKEN_FILE", ""))
+		if auth.TokenHelper != "" {
+			break
+		}
+		if auth.TokenPath == "" {
+			if home, err := os.UserHomeDir(); err == nil {
+				auth.TokenPath = filepath.Join(home, ".vault-token")
+			}
+		}
+		if !isFileReadable(auth.TokenPath) {
+			return errors.New("auth method file requires a readable VAULT_TOKEN_FILE (default ~/.vault-token) or VAULT_TOKEN_HELPER")
 		}
+
+	case "kubernetes":
+		auth.Mount = authMount("kubernetes")
 		auth.Role = strings.TrimSpace(getEnvWithDefault("VAULT_K8S_ROLE", ""))
 		if auth.Role == "" {
 			return errors.New("auth method kubernetes requires VAULT_K8S_ROLE")
@@ -156,18 +291,122 @@ func configureAuthMethod(auth *AuthConfig, method, tokenEnv, defaultJWTPath stri
 		}
 		auth.Audience = strings.TrimSpace(getEnvWithDefault("VAULT_K8S_AUDIENCE", ""))
 
+	case "cert":
+		auth.Mount = authMount("cert")
+		auth.Role = strings.TrimSpace(getEnvWithDefault("VAULT_CERT_ROLE", ""))
+		if auth.ClientCert == "" || auth.ClientKey == "" {
+			return errors.New("auth method cert requires VAULT_CLIENT_CERT and VAULT_CLIENT_KEY")
+		}
+
+	case "approle":
+		auth.Mount = authMount("approle")
+		auth.RoleID = strings.TrimSpace(getEnvWithDefault("VAULT_APPROLE_ROLE_ID", ""))
+		if auth.RoleID == "" {
+			return errors.New("auth method approle requires VAULT_APPROLE_ROLE_ID")
+		}
+		auth.SecretID = strings.TrimSpace(getEnvWithDefault("VAULT_APPROLE_SECRET_ID", ""))
+		auth.SecretIDPath = strings.TrimSpace(getEnvWithDefault("VAULT_APPROLE_SECRET_ID_FILE", ""))
+		if auth.SecretID == "" && auth.SecretIDPath == "" {
+			return errors.New("auth method approle requires VAULT_APPROLE_SECRET_ID or VAULT_APPROLE_SECRET_ID_FILE")
+		}
+		if auth.SecretIDPath != "" && !isFileReadable(auth.SecretIDPath) {
+			return errors.New("auth method approle requires a readable VAULT_APPROLE_SECRET_ID_FILE")
+		}
+		auth.SecretWrapped = parseEnvBool("VAULT_APPROLE_SECRET_ID_WRAPPED", false)
+
+	case "jwt":
+		auth.Mount = authMount("jwt")
+		auth.Role = strings.TrimSpace(getEnvWithDefault("VAULT_JWT_ROLE", ""))
+		auth.JWTPath = strings.TrimSpace(getEnvWithDefault("VAULT_JWT_PATH", ""))
+		auth.JWT = strings.TrimSpace(getEnvWithDefault("VAULT_JWT", ""))
+		if auth.JWTPath == "" && auth.JWT == "" {
+			return errors.New("auth method jwt requires VAULT_JWT_PATH or VAULT_JWT")
+		}
+		if auth.JWTPath != "" && !isFileReadable(auth.JWTPath) {
+			return errors.New("auth method jwt requires a readable VAULT_JWT_PATH")
+		}
+
+	case "azure":
+		auth.Mount = authMount("azure")
+		auth.Role = strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_ROLE", ""))
+		if auth.Role == "" {
+			return errors.New("auth method azure requires VAULT_AZURE_ROLE")
+		}
+		auth.Azure = loadAzureAuthConfig()
+		if auth.Azure.FederatedTokenFile != "" && auth.Azure.TenantID == "" {
+			return errors.New("auth method azure with AZURE_FEDERATED_TOKEN_FILE requires AZURE_TENANT_ID")
+		}
+
 	default:
 		return errors.New("unsupported auth method: " + method)
 	}
 	return nil
 }
 
+// authMount returns VAULT_AUTH_MOUNT, or def when unset or blank.
+func authMount(def string) string {
+	if m := strings.Trim(strings.TrimSpace(getEnvWithDefault("VAULT_AUTH_MOUNT", "")), "/"); m != "" {
+		return m
+	}
+	return def
+}
+
+// loadAzureAuthConfig loads the Azure auth method settings.
+func loadAzureAuthConfig() AzureAuthConfig {
+	return AzureAuthConfig{
+		Resource:           strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_RESOURCE", "https://management.azure.com/")),
+		ClientID:           strings.TrimSpace(getEnvWithDefault("AZURE_CLIENT_ID", "")),
+		TenantID:           strings.TrimSpace(getEnvWithDefault("AZURE_TENANT_ID", "")),
+		FederatedTokenFile: strings.TrimSpace(getEnvWithDefault("AZURE_FEDERATED_TOKEN_FILE", "")),
+		IMDSEndpoint:       strings.TrimSpace(getEnvWithDefault("AZURE_IMDS_ENDPOINT", "")),
+		SubscriptionID:     strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_SUBSCRIPTION_ID", "")),
+		ResourceGroup:      strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_RESOURCE_GROUP", "")),
+		VMName:             strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_VM_NAME", "")),
+		VMSSName:           strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_VMSS_NAME", "")),
+		ResourceID:         strings.TrimSpace(getEnvWithDefault("VAULT_AZURE_RESOURCE_ID", "")),
+	}
+}
+
+// loadVerifyConfig loads the post-restore verification settings.
+func loadVerifyConfig() VerifyConfig {
+	return VerifyConfig{
+		Enabled:       parseEnvBool("RESTORE_VERIFY", false),
+		HealthTimeout: parseEnvDuration("RESTORE_VERIFY_HEALTH_TIMEOUT", 2*time.Minute),
+		IndexTimeout:  parseEnvDuration("RESTORE_VERIFY_INDEX_TIMEOUT", 2*time.Minute),
+		CanaryPath:    strings.Trim(strings.TrimSpace(getEnvWithDefault("RESTORE_VERIFY_CANARY_PATH", "")), "/"),
+		CanaryTimeout: parseEnvDuration("RESTORE_VERIFY_CANARY_TIMEOUT", 30*time.Second),
+		Interval:      parseEnvDuration("RESTORE_VERIFY_INTERVAL", 2*time.Second),
+	}
+}
+
+// loadAutoSnapshotConfig loads the automated snapshot settings.
+func loadAutoSnapshotConfig(backupTarget, accountKey string) AutoSnapshotConfig {
+	prefix := strings.Trim(strings.TrimSpace(backupTarget), "/")
+	if prefix == "" {
+		prefix = "vault/snapshots"
+	}
+	mode := "managed"
+	if accountKey != "" {
+		mode = "shared"
+	}
+	return AutoSnapshotConfig{
+		Name:       strings.TrimSpace(getEnvWithDefault("AUTO_SNAPSHOT_NAME", "vault-raft-backup")),
+		Interval:   parseEnvDuration("AUTO_SNAPSHOT_INTERVAL", time.Hour),
+		Retain:     parseEnvInt("AUTO_SNAPSHOT_RETAIN", 24),
+		PathPrefix: strings.Trim(strings.TrimSpace(getEnvWithDefault("AUTO_SNAPSHOT_PATH_PREFIX", prefix)), "/"),
+		FilePrefix: strings.TrimSpace(getEnvWithDefault("AUTO_SNAPSHOT_FILE_PREFIX", "vault-snapshot")),
+		AuthMode:   strings.ToLower(strings.TrimSpace(getEnvWithDefault("AUTO_SNAPSHOT_AZURE_AUTH_MODE", mode))),
+		Endpoint:   strings.TrimSpace(getEnvWithDefault("AUTO_SNAPSHOT_AZURE_ENDPOINT", "")),
+	}
+}
+
 // loadAzureConfig loads Azure-specific configuration.
 func loadAzureConfig() AzureConfig {
 	return AzureConfig{
 		Account:      getEnvWithDefault("AZURE_STORAGE_ACCOUNT", ""),
 		Container:    getEnvWithDefault("AZURE_STORAGE_CONTAINER", ""),
 		SASToken:     getEnvWithDefault("AZURE_STORAGE_SAS", ""),
+		AccountKey:   getEnvWithDefault("AZURE_STORAGE_KEY", ""),
 		ClientID:     getEnvWithDefault("AZURE_CLIENT_ID", ""),
 		ClientSecret: getEnvWithDefault("AZURE_CLIENT_SECRET", ""),
 		TenantID:     getEnvWithDefault("AZURE_TENANT_ID", ""),
@@ -225,6 +464,17 @@ func parseEnvBool(key string, def bool) bool {
 	return def
 }
 
+// splitList splits a comma-separated value, dropping blank entries.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 // isFileReadable checks if a file exists and is readable.
 func isFileReadable(path string) bool {
 	if strings.TrimSpace(path) == "" {
@@ -238,9 +488,34 @@ func isFileReadable(path string) bool {
 	return true
 }
 
-// validate checks provider-specific requirements.
+// validate checks policy values and provider-specific requirements.
 // For Azure: must have Account+Container and either SAS or Service Principal (or MSI if present in your providers).
 func (c *Config) validate() error {
+	switch c.BackupHealthPolicy {
+	case "fail", "skip":
+	default:
+		return errors.New("BACKUP_HEALTH_POLICY must be fail or skip, got: " + c.BackupHealthPolicy)
+	}
+	switch c.BackupQuorumPolicy {
+	case "warn", "fail":
+	default:
+		return errors.New("BACKUP_QUORUM_POLICY must be warn or fail, got: " + c.BackupQuorumPolicy)
+	}
+	if (c.RaftJoin.LeaderClientCert == "") != (c.RaftJoin.LeaderClientKey == "") {
+		return errors.New("RAFT_JOIN_LEADER_CLIENT_CERT and RAFT_JOIN_LEADER_CLIENT_KEY must be set together")
+	}
+	for _, d := range c.Digests {
+		if !util.ValidDigest(d) {
+			return errors.New("SNAPSHOT_DIGESTS entries must be sha256 or sha512, got: " + d)
+		}
+	}
+
+	switch c.AutoSnapshot.AuthMode {
+	case "shared", "managed":
+	default:
+		return errors.New("AUTO_SNAPSHOT_AZURE_AUTH_MODE must be shared or managed, got: " + c.AutoSnapshot.AuthMode)
+	}
+
 	switch c.Provider {
 	case "azure":
 		if c.Azure.Account == "" || c.Azure.Container == "" {
@@ -263,3 +538,40 @@ func (c Config) RetryOptions() retry.Options {
 		Jitter:       c.RetryEnableJitter,
 	}
 }
+
+// VaultOptions converts Vault connection and TLS settings to vault.Options.
+func (c Config) VaultOptions() vault.Options {
+	return vault.Options{
+		Addr:          c.VaultAddr,
+		CACert:        c.Auth.CACert,
+		CAPath:        c.Auth.CAPath,
+		TLSServerName: c.Auth.TLSServerName,
+		ClientCert:    c.Auth.ClientCert,
+		ClientKey:     c.Auth.ClientKey,
+		SkipVerify:    c.Auth.SkipVerify,
+		Namespace:     c.Auth.Namespace,
+		Digests:       c.Digests,
+	}
+}
+
+// AutoSnapshotOptions builds the Vault automated snapshot configuration that writes
+// to the operator's Azure container, so snapshots land where restore looks for them.
+func (c Config) AutoSnapshotOptions() vault.AutoSnapshotConfig {
+	out := vault.AutoSnapshotConfig{
+		Interval:           int64(c.AutoSnapshot.Interval.Seconds()),
+		Retain:             c.AutoSnapshot.Retain,
+		PathPrefix:         c.AutoSnapshot.PathPrefix,
+		FilePrefix:         c.AutoSnapshot.FilePrefix,
+		StorageType:        "azure-blob",
+		AzureAccountName:   c.Azure.Account,
+		AzureContainerName: c.Azure.Container,
+		AzureAuthMode:      c.AutoSnapshot.AuthMode,
+		AzureEndpoint:      c.AutoSnapshot.Endpoint,
+	}
+	if out.AzureAuthMode == "shared" {
+		out.AzureAccountKey = c.Azure.AccountKey
+	} else {
+		out.AzureClientID = c.Azure.ClientID
+	}
+	return out
+}
@@ -67,6 +67,7 @@ func init() {
 			sas:        sas,
 			authViaSAS: viaSAS,
 			ro:         c.RetryOptions(),
+			digests:    c.Digests,
 		}, nil
 	})
 }
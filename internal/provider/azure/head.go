@@ -7,10 +7,13 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
 )
 
-// headSizeAndSHA does a direct HEAD (SAS) to read Content-Length and x-ms-meta-sha256.
-func (p *AzureProvider) headSizeAndSHA(ctx context.Context, key string) (int64, string, error) {
+// headSizeAndDigests does a direct HEAD (SAS) to read Content-Length and the
+// x-ms-meta-<alg> digest of every algorithm in want.
+func (p *AzureProvider) headSizeAndDigests(ctx context.Context, key string, want util.Digests) (int64, util.Digests, error) {
 	base := p.endpoint
 	if !strings.HasSuffix(base, "/") {
 		base += "/"
@@ -20,27 +23,30 @@ func (p *AzureProvider) headSizeAndSHA(ctx context.Context, key string) (int64,
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, http.NoBody)
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
 	}
 	cli := &http.Client{Timeout: 15 * time.Second}
 	resp, err := cli.Do(req)
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, "", fmt.Errorf("HEAD %s: %s", url, resp.Status)
+		return 0, nil, fmt.Errorf("HEAD %s: %s", url, resp.Status)
 	}
 
 	cl := resp.Header.Get("Content-Length")
 	if cl == "" {
-		return 0, "", fmt.Errorf("missing Content-Length")
+		return 0, nil, fmt.Errorf("missing Content-Length")
 	}
 	n, err := strconv.ParseInt(cl, 10, 64)
 	if err != nil {
-		return 0, "", fmt.Errorf("parse Content-Length: %w", err)
+		return 0, nil, fmt.Errorf("parse Content-Length: %w", err)
+	}
+	sums := make(util.Digests, len(want))
+	for alg := range want {
+		sums[alg] = resp.Header.Get("x-ms-meta-" + alg)
 	}
-	sha := resp.Header.Get("x-ms-meta-sha256")
-	return n, sha, nil
+	return n, sums, nil
 }
@@ -3,6 +3,7 @@ package azure
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -24,27 +25,38 @@ type AzureProvider struct {
 	sas        string // raw SAS without leading "?"
 	authViaSAS bool
 	ro         retry.Options
+	digests    []string // algorithms computed on download and for uploads without digests
 }
 
 func (p *AzureProvider) Name() string { return "azure" }
 
 // Backup uploads file and validates it (HEAD with SAS, list otherwise).
-func (p *AzureProvider) Backup(ctx context.Context, source, target string) error {
+// The digests are stored as blob metadata; they are only computed here when sums is nil.
+func (p *AzureProvider) Backup(ctx context.Context, source, target string, sums util.Digests) error {
 	if err := p.ensureContainer(ctx); err != nil {
 		return fmt.Errorf("ensure container: %w", err)
 	}
 	key := normalizeKey(target)
 
-	sum, size, err := util.SHA256File(source)
-	if err != nil {
-		return fmt.Errorf("checksum: %w", err)
+	var size int64
+	if len(sums) == 0 {
+		var err error
+		if sums, size, err = util.DigestFile(source, p.digests); err != nil {
+			return fmt.Errorf("checksum: %w", err)
+		}
+	} else {
+		st, err := os.Stat(source)
+		if err != nil {
+			return err
+		}
+		size = st.Size()
 	}
 
-	if err := p.uploadWithRetry(ctx, source, key, sum); err != nil {
+	if err := p.uploadWithRetry(ctx, source, key, sums); err != nil {
 		return fmt.Errorf("upload: %w", err)
 	}
 
-	if err := p.validateUpload(ctx, key, sum, size); err != nil {
+	if err := p.validateUpload(ctx, key, sums, size); err != nil {
 		return err
 	}
 
@@ -52,7 +64,11 @@ func (p *AzureProvider) Backup(ctx context.Context, source, target string) error
 }
 
 // uploadWithRetry uploads a file to Azure Blob Storage with retry logic.
-func (p *AzureProvider) uploadWithRetry(ctx context.Context, source, key, sum string) error {
+func (p *AzureProvider) uploadWithRetry(ctx context.Context, source, key string, sums util.Digests) error {
+	meta := make(map[string]*string, len(sums))
+	for alg, sum := range sums {
+		meta[alg] = to.Ptr(sum)
+	}
 	upStart := time.Now()
 	upAttempt := 0
 	uploadOnce := func(ctx context.Context) error {
@@ -77,7 +93,7 @@ func (p *AzureProvider) uploadWithRetry(ctx context.Context, source, key, sum st
 			}
 		}()
 		_, err = p.client.UploadFile(ctx, p.container, key, f, &azblob.UploadFileOptions{
-			Metadata: map[string]*string{"sha256": to.Ptr(sum)},
+			Metadata: meta,
 		})
 		if err != nil {
 			log.Debug().Err(err).Str("action", "azure_upload").Str("container", p.container).Str("key", key).
@@ -98,15 +114,15 @@ func (p *AzureProvider) uploadWithRetry(ctx context.Context, source, key, sum st
 }
 
 // validateUpload validates the uploaded file using HEAD (with SAS) or LIST (without SAS).
-func (p *AzureProvider) validateUpload(ctx context.Context, key, sum string, size int64) error {
+func (p *AzureProvider) validateUpload(ctx context.Context, key string, sums util.Digests, size int64) error {
 	if p.authViaSAS {
-		return p.validateWithHead(ctx, key, sum, size)
+		return p.validateWithHead(ctx, key, sums, size)
 	}
 	return p.validateWithList(ctx, key, size)
 }
 
 // validateWithHead validates upload using HEAD request (requires SAS).
-func (p *AzureProvider) validateWithHead(ctx context.Context, key, sum string, size int64) error {
+func (p *AzureProvider) validateWithHead(ctx context.Context, key string, sums util.Digests, size int64) error {
 	headStart := time.Now()
 	headAttempt := 0
 	headOnce := func(ctx context.Context) error {
@@ -114,7 +130,7 @@ func (p *AzureProvider) validateWithHead(ctx context.Context, key, sum string, s
 		log.Debug().Str("action", "azure_head").Str("container", p.container).Str("key", key).
 			Int("attempt", headAttempt).Msg("starting attempt")
 
-		remoteSize, remoteSHA, err := p.headSizeAndSHA(ctx, key)
+		remoteSize, remoteSums, err := p.headSizeAndDigests(ctx, key, sums)
 		if err != nil {
 			log.Debug().Err(err).Str("action", "azure_head").Str("container", p.container).Str("key", key).
 				Int("attempt", headAttempt).Msg("attempt failed")
@@ -123,11 +139,13 @@ func (p *AzureProvider) validateWithHead(ctx context.Context, key, sum string, s
 		if remoteSize != size {
 			return fmt.Errorf("size mismatch: local=%d, remote=%d", size, remoteSize)
 		}
-		if remoteSHA == "" {
-			return fmt.Errorf("missing metadata: sha256")
-		}
-		if remoteSHA != sum {
-			return fmt.Errorf("sha256 mismatch: local=%s, remote=%s", sum, remoteSHA)
+		for alg, sum := range sums {
+			switch remote := remoteSums[alg]; {
+			case remote == "":
+				return fmt.Errorf("missing metadata: %s", alg)
+			case remote != sum:
+				return fmt.Errorf("%s mismatch: local=%s, remote=%s", alg, sum, remote)
+			}
 		}
 
 		log.Debug().Str("action", "azure_head").Str("container", p.container).Str("key", key).
@@ -139,7 +157,7 @@ func (p *AzureProvider) validateWithHead(ctx context.Context, key, sum string, s
 	}
 	log.Info().Str("action", "azure_head").Str("container", p.container).Str("key", key).
 		Int("attempts", headAttempt).Dur("elapsed_ms", time.Since(headStart)).
-		Msg("validation OK (sha256 & size)")
+		Msg("validation OK (digests & size)")
 	return nil
 }
 
@@ -177,9 +195,14 @@ func (p *AzureProvider) validateWithList(ctx context.Context, key string, size i
 	return nil
 }
 
-// Restore downloads a blob to a local path with retries.
-func (p *AzureProvider) Restore(ctx context.Context, source, target string) error {
+// Restore downloads a blob to a local path with retries, digesting it as it is written.
+// A digest that differs from the blob's metadata fails the download.
+func (p *AzureProvider) Restore(ctx context.Context, source, target string) (util.Digests, error) {
 	key := normalizeKey(source)
+	hasher, err := util.NewHasher(p.digests)
+	if err != nil {
+		return nil, err
+	}
 
 	dlStart := time.Now()
 	dlAttempt := 0
@@ -200,22 +223,51 @@ func (p *AzureProvider) Restore(ctx context.Context, source, target string) erro
 					Msg("failed to close local file after download")
 			}
 		}()
-		_, err = p.client.DownloadFile(ctx, p.container, key, out, nil)
+		resp, err := p.client.DownloadStream(ctx, p.container, key, nil)
 		if err != nil {
 			log.Debug().Err(err).Str("action", "azure_download").Str("container", p.container).Str("key", key).
 				Int("attempt", dlAttempt).Msg("attempt failed")
 			return err
 		}
+		body := resp.NewRetryReader(ctx, nil)
+		defer func() { _ = body.Close() }()
+
+		hasher.Reset()
+		if _, err := io.Copy(io.MultiWriter(out, hasher), body); err != nil {
+			log.Debug().Err(err).Str("action", "azure_download").Str("container", p.container).Str("key", key).
+				Int("attempt", dlAttempt).Msg("attempt failed")
+			return err
+		}
+		if err := checkDigests(hasher.Sum(), resp.Metadata); err != nil {
+			return err
+		}
 
 		log.Debug().Str("action", "azure_download").Str("container", p.container).Str("key", key).
 			Int("attempt", dlAttempt).Msg("attempt succeeded")
 		return nil
 	}
 	if err := retry.Do(ctx, p.ro, p.isAzRetryable, downloadOnce); err != nil {
-		return err
+		return nil, err
 	}
+	sums := hasher.Sum()
 	log.Info().Str("action", "azure_download").Str("container", p.container).Str("key", key).
-		Str("local", target).Int("attempts", dlAttempt).Dur("elapsed_ms", time.Since(dlStart)).Msg("download OK")
+		Str("local", target).Int64("size", hasher.Size()).Str("digests", sums.String()).
+		Int("attempts", dlAttempt).Dur("elapsed_ms", time.Since(dlStart)).Msg("download OK")
+	return sums, nil
+}
+
+// checkDigests compares downloaded digests with those stored as blob metadata at upload.
+// Blobs uploaded by other tools carry no metadata and are accepted as is.
+func checkDigests(sums util.Digests, meta map[string]*string) error {
+	for k, v := range meta {
+		alg := strings.ToLower(k)
+		if v == nil || sums[alg] == "" {
+			continue
+		}
+		if !strings.EqualFold(*v, sums[alg]) {
+			return fmt.Errorf("%s mismatch: remote metadata=%s, downloaded=%s", alg, *v, sums[alg])
+		}
+	}
 	return nil
 }
 
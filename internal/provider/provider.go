@@ -1,15 +1,22 @@
 package provider
 
-import "context"
+import (
+	"context"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+)
 
 // Provider defines the contract for storage backends used by the operator.
 // Paths/keys are plain strings so implementations can decide their own format.
 type Provider interface {
 	// Backup uploads local data (source) to remote storage (target).
-	Backup(ctx context.Context, source, target string) error
+	// sums are the source digests computed while it was written; when nil the
+	// provider computes them itself.
+	Backup(ctx context.Context, source, target string, sums util.Digests) error
 
-	// Restore downloads remote data (source) to a local path (target).
-	Restore(ctx context.Context, source, target string) error
+	// Restore downloads remote data (source) to a local path (target) and returns
+	// the digests computed while it was written.
+	Restore(ctx context.Context, source, target string) (util.Digests, error)
 
 	// Name returns the provider identifier (e.g. "azure", "s3").
 	Name() string
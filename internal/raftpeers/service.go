@@ -0,0 +1,242 @@
+// Package raftpeers lists, removes and joins raft peers to finish rebuilding a
+// cluster after a restore (operator raft peers|remove-peer|join).
+package raftpeers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// ErrUsage is returned for an unknown subcommand or missing arguments.
+var ErrUsage = errors.New("usage: operator raft peers | remove-peer <node_id>...|--stale | join <node_addr>... [--leader <api_addr>]")
+
+// Peer merges the raft configuration with autopilot health.
+type Peer struct {
+	NodeID     string `json:"node_id"`
+	Address    string `json:"address"`
+	Leader     bool   `json:"leader"`
+	Voter      bool   `json:"voter"`
+	Healthy    *bool  `json:"healthy,omitempty"`
+	NodeStatus string `json:"node_status,omitempty"`
+}
+
+// JoinResult is the outcome of one join request.
+type JoinResult struct {
+	Node   string `json:"node"`
+	Leader string `json:"leader"`
+	Joined bool   `json:"joined"`
+}
+
+// Run executes a raft subcommand and writes its result as JSON to out.
+func Run(ctx context.Context, cfg config.Config, sub string, args []string, out io.Writer) error {
+	sub = strings.ToLower(strings.TrimSpace(sub))
+	switch sub {
+	case "peers":
+	case "remove-peer", "join":
+		if len(args) == 0 {
+			return ErrUsage
+		}
+	default:
+		return ErrUsage
+	}
+
+	vc, err := vault.NewClient(cfg.VaultOptions())
+	if err != nil {
+		return fmt.Errorf("vault client: %w", err)
+	}
+	if err := vc.SelectNode(ctx); err != nil {
+		return err
+	}
+
+	var result any
+	if sub == "join" {
+		result, err = join(ctx, cfg, vc, args)
+	} else {
+		result, err = withSession(ctx, cfg, vc, func(sess *auth.Session) (any, error) {
+			if sub == "peers" {
+				return listPeers(ctx, vc, sess)
+			}
+			return removePeers(ctx, vc, sess, args)
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("action", "raft").Str("command", sub).Msg("raft command failed")
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// withSession runs fn with an authenticated session that is revoked afterwards.
+func withSession(ctx context.Context, cfg config.Config, vc *vault.Client, fn func(*auth.Session) (any, error)) (any, error) {
+	sess, err := auth.Start(ctx, cfg, vc)
+	if err != nil {
+		log.Error().Err(err).Str("action", "raft_auth").Str("method", cfg.Auth.Method).Msg("vault auth failed")
+		return nil, err
+	}
+	defer sess.Close()
+	return fn(sess)
+}
+
+// listPeers reads the raft configuration and, when permitted, autopilot health.
+func listPeers(ctx context.Context, vc *vault.Client, sess *auth.Session) ([]Peer, error) {
+	var rc vault.RaftConfiguration
+	err := sess.Do(ctx, func(token string) (err error) {
+		rc, err = vc.RaftConfiguration(ctx, token)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ap vault.AutopilotState
+	err = sess.Do(ctx, func(token string) (err error) {
+		ap, err = vc.AutopilotState(ctx, token)
+		return err
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("action", "raft_peers").Msg("autopilot state unavailable; peer health unknown")
+	}
+
+	peers := make([]Peer, 0, len(rc.Servers))
+	for _, s := range rc.Servers {
+		p := Peer{NodeID: s.NodeID, Address: s.Address, Leader: s.Leader, Voter: s.Voter}
+		if a, ok := ap.Servers[s.NodeID]; ok {
+			p.Healthy, p.NodeStatus = &a.Healthy, a.NodeStatus
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+// removePeers removes the given node IDs, or with "--stale" every non-leader peer
+// that autopilot no longer sees alive. The leader and unknown IDs are refused, and
+// "--stale" cannot be combined with explicit IDs.
+func removePeers(ctx context.Context, vc *vault.Client, sess *auth.Session, args []string) ([]string, error) {
+	if slices.Contains(args, "--stale") && len(args) > 1 {
+		return nil, ErrUsage
+	}
+	peers, err := listPeers(ctx, vc, sess)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	if slices.Contains(args, "--stale") {
+		for _, p := range peers {
+			if p.Healthy == nil {
+				return nil, errors.New("remove-peer --stale needs autopilot state (read on sys/storage/raft/autopilot/state)")
+			}
+			if !p.Leader && p.NodeStatus != "alive" {
+				ids = append(ids, p.NodeID)
+			}
+		}
+	} else {
+		ids = args
+	}
+
+	for _, id := range ids {
+		i := slices.IndexFunc(peers, func(p Peer) bool { return p.NodeID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("remove-peer: %q is not a raft peer", id)
+		}
+		if peers[i].Leader {
+			return nil, fmt.Errorf("remove-peer: %q is the leader; refusing", id)
+		}
+	}
+
+	removed := []string{}
+	for _, id := range ids {
+		err := sess.Do(ctx, func(token string) error {
+			return vc.RemovePeer(ctx, token, id)
+		})
+		if err != nil {
+			return removed, err
+		}
+		removed = append(removed, id)
+		log.Info().Str("action", "raft_remove_peer").Str("node_id", id).Msg("raft peer removed")
+	}
+	return removed, nil
+}
+
+// join asks each node to join the leader (RAFT_JOIN_LEADER_ADDR or --leader, else the
+// leader of VAULT_ADDR). Join is unauthenticated: the joining node is not yet a member.
+func join(ctx context.Context, cfg config.Config, vc *vault.Client, args []string) ([]JoinResult, error) {
+	leader := cfg.RaftJoin.LeaderAddr
+	var nodes []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--leader" && i+1 < len(args) {
+			leader = args[i+1]
+			i++
+			continue
+		}
+		nodes = append(nodes, strings.TrimRight(strings.TrimSpace(args[i]), "/"))
+	}
+	if len(nodes) == 0 {
+		return nil, ErrUsage
+	}
+	if leader == "" {
+		l, err := vc.Leader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("find leader: %w", err)
+		}
+		leader = l.LeaderAddress
+		if leader == "" && l.IsSelf {
+			leader = vc.Addr()
+		}
+		if leader == "" {
+			return nil, errors.New("no active leader found; pass --leader or set RAFT_JOIN_LEADER_ADDR")
+		}
+	}
+
+	req := vault.JoinRequest{LeaderAPIAddr: leader, NonVoter: cfg.RaftJoin.NonVoter, Retry: cfg.RaftJoin.Retry}
+	pems := []struct {
+		env, path string
+		dst       *string
+	}{
+		{"RAFT_JOIN_LEADER_CACERT", cfg.RaftJoin.LeaderCACert, &req.LeaderCACert},
+		{"RAFT_JOIN_LEADER_CLIENT_CERT", cfg.RaftJoin.LeaderClientCert, &req.LeaderClientCert},
+		{"RAFT_JOIN_LEADER_CLIENT_KEY", cfg.RaftJoin.LeaderClientKey, &req.LeaderClientKey},
+	}
+	for _, p := range pems {
+		if p.path == "" {
+			continue
+		}
+		pem, err := os.ReadFile(p.path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", p.env, err)
+		}
+		*p.dst = string(pem)
+	}
+
+	results := make([]JoinResult, 0, len(nodes))
+	for _, node := range nodes {
+		joined, err := vc.AtNode(node).Join(ctx, req)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", node, err)
+		}
+		results = append(results, JoinResult{Node: node, Leader: leader, Joined: joined})
+		log.Info().
+			Str("action", "raft_join").
+			Str("node", node).
+			Str("leader", leader).
+			Bool("joined", joined).
+			Bool("non_voter", req.NonVoter).
+			Msg("raft join")
+	}
+	return results, nil
+}
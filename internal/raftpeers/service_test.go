@@ -0,0 +1,165 @@
+package raftpeers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// fakeCluster serves a three-node raft cluster: vault-0 leads, vault-1 is alive
+// and vault-2 has left. It records remove-peer IDs and join bodies.
+type fakeCluster struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	removed []string
+	joins   []vault.JoinRequest
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	t.Helper()
+	f := &fakeCluster{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			_, _ = w.Write([]byte(`{"data":{"ttl":0,"renewable":false}}`))
+		case "/v1/sys/leader":
+			_, _ = w.Write([]byte(`{"is_self":false,"leader_address":"https://vault-0:8200"}`))
+		case "/v1/sys/storage/raft/configuration":
+			_, _ = w.Write([]byte(`{"data":{"config":{"servers":[` +
+				`{"node_id":"vault-0","address":"vault-0:8201","leader":true,"voter":true},` +
+				`{"node_id":"vault-1","address":"vault-1:8201","voter":true},` +
+				`{"node_id":"vault-2","address":"vault-2:8201","voter":true}]}}}`))
+		case "/v1/sys/storage/raft/autopilot/state":
+			_, _ = w.Write([]byte(`{"data":{"servers":{` +
+				`"vault-0":{"node_status":"alive","healthy":true},` +
+				`"vault-1":{"node_status":"alive","healthy":true},` +
+				`"vault-2":{"node_status":"left","healthy":false}}}}`))
+		case "/v1/sys/storage/raft/remove-peer":
+			var in struct {
+				ServerID string `json:"server_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			f.removed = append(f.removed, in.ServerID)
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/sys/storage/raft/join":
+			var in vault.JoinRequest
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			f.joins = append(f.joins, in)
+			_, _ = w.Write([]byte(`{"joined":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeCluster) config() config.Config {
+	return config.Config{
+		VaultAddr: f.URL,
+		Auth:      config.AuthConfig{Method: "token", Token: "s.operator"},
+	}
+}
+
+func TestRemovePeer(t *testing.T) {
+	cases := []struct {
+		name        string
+		args        []string
+		wantErr     string
+		wantUsage   bool
+		wantRemoved []string
+	}{
+		{"leader refused", []string{"vault-0"}, "is the leader", false, nil},
+		{"unknown id refused", []string{"vault-1", "vault-9"}, "not a raft peer", false, nil},
+		{"stale selects dead non-leaders", []string{"--stale"}, "", false, []string{"vault-2"}},
+		{"stale with ids is a usage error", []string{"--stale", "vault-1"}, "", true, nil},
+		{"explicit id", []string{"vault-1"}, "", false, []string{"vault-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFakeCluster(t)
+			var out bytes.Buffer
+			err := Run(context.Background(), f.config(), "remove-peer", tc.args, &out)
+			switch {
+			case tc.wantUsage:
+				if !errors.Is(err, ErrUsage) {
+					t.Fatalf("want ErrUsage, got %v", err)
+				}
+			case tc.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("want error containing %q, got %v", tc.wantErr, err)
+				}
+			case err != nil:
+				t.Fatalf("Run: %v", err)
+			}
+			// Refusals happen before any peer is removed.
+			if !slices.Equal(f.removed, tc.wantRemoved) {
+				t.Fatalf("removed: want %v, got %v", tc.wantRemoved, f.removed)
+			}
+		})
+	}
+}
+
+func TestJoin_LeaderResolution(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured string
+		args       []string
+		want       string
+	}{
+		{"flag wins", "https://configured:8200", []string{"--leader", "https://flag:8200"}, "https://flag:8200"},
+		{"config before sys/leader", "https://configured:8200", nil, "https://configured:8200"},
+		{"sys/leader fallback", "", nil, "https://vault-0:8200"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFakeCluster(t)
+			cfg := f.config()
+			cfg.RaftJoin.LeaderAddr = tc.configured
+			var out bytes.Buffer
+			if err := Run(context.Background(), cfg, "join", append([]string{f.URL}, tc.args...), &out); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if len(f.joins) != 1 || f.joins[0].LeaderAPIAddr != tc.want {
+				t.Fatalf("want join to %s, got %+v", tc.want, f.joins)
+			}
+		})
+	}
+}
+
+func TestJoin_SendsLeaderClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certPath, []byte("CERT"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, []byte("KEY"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFakeCluster(t)
+	cfg := f.config()
+	cfg.RaftJoin.LeaderClientCert, cfg.RaftJoin.LeaderClientKey = certPath, keyPath
+	var out bytes.Buffer
+	if err := Run(context.Background(), cfg, "join", []string{f.URL}, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(f.joins) != 1 || f.joins[0].LeaderClientCert != "CERT" || f.joins[0].LeaderClientKey != "KEY" {
+		t.Fatalf("client certificate not sent: %+v", f.joins)
+	}
+}
@@ -0,0 +1,121 @@
+// Package raftsnap reads Vault raft snapshot archives.
+//
+// A snapshot saved from sys/storage/raft/snapshot is a gzip-compressed tar
+// holding meta.json (raft snapshot metadata), state.bin (the FSM data) and
+// SHA256SUMS (checksums of the two other files).
+package raftsnap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Archive member names.
+const (
+	FileMeta  = "meta.json"
+	FileState = "state.bin"
+	FileSums  = "SHA256SUMS"
+)
+
+// Meta is the raft snapshot metadata stored in meta.json.
+type Meta struct {
+	Version            int           `json:"Version"`
+	ID                 string        `json:"ID"`
+	Index              uint64        `json:"Index"`
+	Term               uint64        `json:"Term"`
+	Configuration      Configuration `json:"Configuration"`
+	ConfigurationIndex uint64        `json:"ConfigurationIndex"`
+	Size               int64         `json:"Size"`
+}
+
+// Configuration is the raft membership captured in the snapshot.
+type Configuration struct {
+	Servers []Server `json:"Servers"`
+}
+
+// Server is a raft peer. Suffrage is 0 for voters, 1 for non-voters, 2 for staging.
+type Server struct {
+	Suffrage int    `json:"Suffrage"`
+	ID       string `json:"ID"`
+	Address  string `json:"Address"`
+}
+
+// Voter reports whether the peer votes in elections.
+func (s Server) Voter() bool { return s.Suffrage == 0 }
+
+// ErrNotFound is returned when an archive member is missing.
+var ErrNotFound = errors.New("member not found in snapshot archive")
+
+// Walk calls fn for every regular file of the snapshot archive at path, in archive order.
+// fn must consume r before returning; returning an error stops the walk.
+func Walk(path string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return WalkReader(f, fn)
+}
+
+// WalkReader is Walk over an already opened archive stream.
+func WalkReader(r io.Reader, fn func(hdr *tar.Header, r io.Reader) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("snapshot is not gzip: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			// Read to the gzip trailer so a stream cut after the tar footer still fails its CRC check.
+			if _, err := io.Copy(io.Discard, gz); err != nil {
+				return fmt.Errorf("read snapshot gzip: %w", err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read snapshot tar: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// errStop ends a walk early once the wanted member was read.
+var errStop = errors.New("stop")
+
+// ReadMeta decodes meta.json from the snapshot archive at path.
+func ReadMeta(path string) (Meta, error) {
+	var (
+		meta  Meta
+		found bool
+	)
+	err := Walk(path, func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Name != FileMeta {
+			return nil
+		}
+		if err := json.NewDecoder(r).Decode(&meta); err != nil {
+			return fmt.Errorf("decode %s: %w", FileMeta, err)
+		}
+		found = true
+		return errStop
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return Meta{}, err
+	}
+	if !found {
+		return Meta{}, fmt.Errorf("%s: %w", FileMeta, ErrNotFound)
+	}
+	return meta, nil
+}
@@ -0,0 +1,88 @@
+package raftsnap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeArchive writes a gzip tar with the given members (in order) and returns its path.
+func writeArchive(t *testing.T, members ...[2]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, m := range members {
+		if err := tw.WriteHeader(&tar.Header{Name: m[0], Mode: 0o600, Size: int64(len(m[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(m[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), "test.snap")
+	if err := os.WriteFile(p, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+const testMeta = `{"Version":1,"ID":"2-120-1700000000000","Index":120,"Term":2,"Peers":"","Configuration":{"Servers":[{"Suffrage":0,"ID":"vault-0","Address":"vault-0:8201"},{"Suffrage":1,"ID":"vault-1","Address":"vault-1:8201"}]},"ConfigurationIndex":1,"Size":42}`
+
+func TestReadMeta(t *testing.T) {
+	p := writeArchive(t, [2]string{FileMeta, testMeta}, [2]string{FileState, "state"})
+
+	m, err := ReadMeta(p)
+	if err != nil {
+		t.Fatalf("ReadMeta: %v", err)
+	}
+	if m.Index != 120 || m.Term != 2 || len(m.Configuration.Servers) != 2 {
+		t.Fatalf("unexpected meta: %+v", m)
+	}
+	if !m.Configuration.Servers[0].Voter() || m.Configuration.Servers[1].Voter() {
+		t.Fatalf("suffrage not decoded: %+v", m.Configuration.Servers)
+	}
+
+	if _, err := ReadMeta(writeArchive(t, [2]string{FileState, "state"})); err == nil {
+		t.Fatal("expected error for archive without meta.json")
+	}
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerify(t *testing.T) {
+	sums := sha(testMeta) + "  meta.json\n" + sha("state") + "  state.bin\n"
+
+	r, err := Verify(writeArchive(t, [2]string{FileMeta, testMeta}, [2]string{FileState, "state"}, [2]string{FileSums, sums}))
+	if err != nil || !r.OK {
+		t.Fatalf("valid archive: ok=%v err=%v files=%+v", r.OK, err, r.Files)
+	}
+
+	r, err = Verify(writeArchive(t, [2]string{FileMeta, testMeta}, [2]string{FileState, "tampered"}, [2]string{FileSums, sums}))
+	if err != nil || r.OK || r.Files[1].Status != StatusMismatch {
+		t.Fatalf("tampered state.bin: ok=%v err=%v files=%+v", r.OK, err, r.Files)
+	}
+
+	r, err = Verify(writeArchive(t, [2]string{FileMeta, testMeta}, [2]string{FileSums, sums}))
+	if err != nil || r.OK || r.Files[1].Name != FileState || r.Files[1].Status != StatusMissing {
+		t.Fatalf("missing state.bin: ok=%v err=%v files=%+v", r.OK, err, r.Files)
+	}
+
+	if _, err := Verify(writeArchive(t, [2]string{FileMeta, testMeta}, [2]string{FileState, "state"})); err == nil {
+		t.Fatal("want error without SHA256SUMS")
+	}
+}
@@ -0,0 +1,188 @@
+package raftsnap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// state.bin format.
+//
+// Vault's raft FSM snapshot (physical/raft FSM.writeTo) is not a BoltDB file image:
+// it is a stream of length-delimited protobuf StorageEntry messages
+// (uvarint length, then key = field 1, value = field 2), one per storage key.
+// Values are barrier-encrypted, so only key paths and sizes are meaningful offline.
+const (
+	StateFormatProto = "protobuf-stream"
+
+	maxEntrySize = 512 << 20 // larger than any Vault storage entry
+)
+
+// boltMagic is the BoltDB meta page magic number (little endian, right after the
+// 16-byte page header of page 0).
+const boltMagic = 0xED0CDAED
+
+// ErrBoltDB is returned when state.bin is a raw BoltDB image (e.g. a copied vault.db),
+// which is not what sys/storage/raft/snapshot produces.
+var ErrBoltDB = errors.New("state.bin is a BoltDB image, not a Vault raft snapshot stream")
+
+// Logical prefixes used to group storage keys.
+const (
+	PrefixCore     = "core"
+	PrefixSys      = "sys"
+	PrefixPolicies = "policies"
+	PrefixLogical  = "logical"
+	PrefixAuth     = "auth"
+	PrefixOther    = "other"
+)
+
+// PrefixStats counts entries and bytes under a prefix.
+type PrefixStats struct {
+	Entries    int64 `json:"entries"`
+	KeyBytes   int64 `json:"key_bytes"`
+	ValueBytes int64 `json:"value_bytes"`
+}
+
+func (p *PrefixStats) add(key string, value int) {
+	p.Entries++
+	p.KeyBytes += int64(len(key))
+	p.ValueBytes += int64(value)
+}
+
+// StateStats summarizes state.bin by logical prefix and by mount.
+type StateStats struct {
+	Format   string                 `json:"format"`
+	Total    PrefixStats            `json:"total"`
+	Prefixes map[string]PrefixStats `json:"prefixes"`
+	// Mounts groups logical/<uuid>/ and auth/<uuid>/ keys per mount.
+	Mounts map[string]PrefixStats `json:"mounts,omitempty"`
+}
+
+// ReadStateStats parses a state.bin stream and aggregates key/value sizes.
+func ReadStateStats(r io.Reader) (StateStats, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	st := StateStats{Format: StateFormatProto, Prefixes: map[string]PrefixStats{}, Mounts: map[string]PrefixStats{}}
+
+	if head, err := br.Peek(20); err == nil && binary.LittleEndian.Uint32(head[16:20]) == boltMagic {
+		return st, ErrBoltDB
+	}
+
+	var buf []byte
+	for {
+		n, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return st, nil
+		}
+		if err != nil {
+			return st, fmt.Errorf("state.bin entry %d: %w", st.Total.Entries+1, err)
+		}
+		if n > maxEntrySize {
+			return st, fmt.Errorf("state.bin entry %d: size %d exceeds limit", st.Total.Entries+1, n)
+		}
+		if uint64(cap(buf)) < n {
+			buf = make([]byte, n)
+		}
+		buf = buf[:n]
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return st, fmt.Errorf("state.bin entry %d: truncated: %w", st.Total.Entries+1, err)
+		}
+		key, valueLen, err := decodeEntry(buf)
+		if err != nil {
+			return st, fmt.Errorf("state.bin entry %d: %w", st.Total.Entries+1, err)
+		}
+
+		st.Total.add(key, valueLen)
+		prefix, mount := classifyKey(key)
+		p := st.Prefixes[prefix]
+		p.add(key, valueLen)
+		st.Prefixes[prefix] = p
+		if mount != "" {
+			m := st.Mounts[mount]
+			m.add(key, valueLen)
+			st.Mounts[mount] = m
+		}
+	}
+}
+
+// decodeEntry extracts the key and value length of a StorageEntry message.
+func decodeEntry(b []byte) (string, int, error) {
+	var (
+		key      string
+		valueLen int
+	)
+	r := bytes.NewReader(b)
+	for r.Len() > 0 {
+		tag, err := binary.ReadUvarint(r)
+		if err != nil {
+			return "", 0, err
+		}
+		field, wire := tag>>3, tag&7
+		switch wire {
+		case 0: // varint
+			if _, err := binary.ReadUvarint(r); err != nil {
+				return "", 0, err
+			}
+		case 1: // 64-bit
+			if _, err := r.Seek(8, io.SeekCurrent); err != nil {
+				return "", 0, err
+			}
+		case 5: // 32-bit
+			if _, err := r.Seek(4, io.SeekCurrent); err != nil {
+				return "", 0, err
+			}
+		case 2: // length-delimited
+			l, err := binary.ReadUvarint(r)
+			if err != nil {
+				return "", 0, err
+			}
+			if l > uint64(r.Len()) {
+				return "", 0, errors.New("field overruns entry")
+			}
+			switch field {
+			case 1:
+				kb := make([]byte, l)
+				_, _ = r.Read(kb)
+				key = string(kb)
+				continue
+			case 2:
+				valueLen = int(l)
+			}
+			if _, err := r.Seek(int64(l), io.SeekCurrent); err != nil {
+				return "", 0, err
+			}
+		default:
+			return "", 0, fmt.Errorf("unsupported protobuf wire type %d", wire)
+		}
+	}
+	if key == "" {
+		return "", 0, errors.New("entry without key")
+	}
+	return key, valueLen, nil
+}
+
+// classifyKey maps a storage key to its logical prefix and, for mounts, "logical/<uuid>".
+func classifyKey(key string) (prefix, mount string) {
+	first, rest, _ := strings.Cut(key, "/")
+	switch first {
+	case "core":
+		return PrefixCore, ""
+	case "sys":
+		if strings.HasPrefix(rest, "policy/") || strings.HasPrefix(rest, "policies/") {
+			return PrefixPolicies, ""
+		}
+		return PrefixSys, ""
+	case "logical", "auth":
+		if id, _, ok := strings.Cut(rest, "/"); ok && id != "" {
+			mount = first + "/" + id
+		}
+		if first == "auth" {
+			return PrefixAuth, mount
+		}
+		return PrefixLogical, mount
+	}
+	return PrefixOther, ""
+}
@@ -0,0 +1,66 @@
+package raftsnap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// entry encodes one length-delimited StorageEntry{key: 1, value: 2}.
+func entry(key string, valueLen int) []byte {
+	var msg []byte
+	msg = append(msg, 0x0a)
+	msg = binary.AppendUvarint(msg, uint64(len(key)))
+	msg = append(msg, key...)
+	msg = append(msg, 0x12)
+	msg = binary.AppendUvarint(msg, uint64(valueLen))
+	msg = append(msg, make([]byte, valueLen)...)
+	return append(binary.AppendUvarint(nil, uint64(len(msg))), msg...)
+}
+
+func TestReadStateStats(t *testing.T) {
+	var stream bytes.Buffer
+	for _, e := range []struct {
+		key string
+		n   int
+	}{
+		{"core/mounts", 100},
+		{"sys/policy/admin", 20},
+		{"sys/token/id/abc", 30},
+		{"logical/1111-aaaa/data/app", 40},
+		{"logical/1111-aaaa/data/db", 50},
+		{"auth/2222-bbbb/role/ci", 10},
+		{"misc", 1},
+	} {
+		stream.Write(entry(e.key, e.n))
+	}
+
+	st, err := ReadStateStats(&stream)
+	if err != nil {
+		t.Fatalf("ReadStateStats: %v", err)
+	}
+	if st.Total.Entries != 7 || st.Total.ValueBytes != 251 {
+		t.Fatalf("total: %+v", st.Total)
+	}
+	want := map[string]int64{PrefixCore: 1, PrefixPolicies: 1, PrefixSys: 1, PrefixLogical: 2, PrefixAuth: 1, PrefixOther: 1}
+	for p, n := range want {
+		if st.Prefixes[p].Entries != n {
+			t.Fatalf("%s: want %d entries, got %+v", p, n, st.Prefixes[p])
+		}
+	}
+	if m := st.Mounts["logical/1111-aaaa"]; m.Entries != 2 || m.ValueBytes != 90 {
+		t.Fatalf("mount stats: %+v", m)
+	}
+
+	truncated := entry("core/x", 10)
+	if _, err := ReadStateStats(bytes.NewReader(truncated[:len(truncated)-3])); err == nil {
+		t.Fatal("want error for truncated entry")
+	}
+
+	bolt := make([]byte, 64)
+	binary.LittleEndian.PutUint32(bolt[16:], boltMagic)
+	if _, err := ReadStateStats(bytes.NewReader(bolt)); !errors.Is(err, ErrBoltDB) {
+		t.Fatalf("want ErrBoltDB, got %v", err)
+	}
+}
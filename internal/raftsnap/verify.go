@@ -0,0 +1,226 @@
+package raftsnap
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Member is one regular file of the archive with its computed digest.
+type Member struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Archive is the result of one pass over a snapshot archive.
+type Archive struct {
+	Path    string            `json:"path"`
+	Size    int64             `json:"size"` // compressed archive size on disk
+	Members []Member          `json:"files"`
+	Meta    *Meta             `json:"meta,omitempty"`
+	Sums    map[string]string `json:"-"` // parsed SHA256SUMS: name → hex digest
+	// State holds state.bin statistics when requested with ScanOptions.Stats.
+	State      *StateStats `json:"state,omitempty"`
+	StateError string      `json:"state_error,omitempty"`
+}
+
+// ScanOptions selects optional work done during Scan.
+type ScanOptions struct {
+	// Stats parses state.bin and aggregates entry counts and sizes per prefix.
+	Stats bool
+}
+
+// Scan reads the archive once, hashing every member and decoding meta.json and SHA256SUMS.
+func Scan(path string, opts ScanOptions) (Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Archive{Path: path}, err
+	}
+	defer func() { _ = f.Close() }()
+	a, err := ScanReader(f, opts)
+	a.Path = path
+	if st, serr := f.Stat(); serr == nil {
+		a.Size = st.Size()
+	}
+	return a, err
+}
+
+// ScanReader is Scan over an archive stream, e.g. while it is being downloaded.
+// Size and Path are left for the caller to fill in.
+func ScanReader(src io.Reader, opts ScanOptions) (Archive, error) {
+	var a Archive
+	err := WalkReader(src, func(hdr *tar.Header, r io.Reader) error {
+		h := sha256.New()
+		var keep bytes.Buffer
+		w := io.Writer(h)
+		if hdr.Name == FileMeta || hdr.Name == FileSums {
+			w = io.MultiWriter(h, &keep)
+		}
+		if hdr.Name == FileState && opts.Stats {
+			// Parse while hashing; a parse error is reported but the digest still completes.
+			cr := &countingReader{r: io.TeeReader(r, w)}
+			st, serr := ReadStateStats(cr)
+			if serr != nil {
+				a.StateError = serr.Error()
+			} else {
+				a.State = &st
+			}
+			if _, err := io.Copy(io.Discard, cr); err != nil {
+				return fmt.Errorf("read %s: %w", hdr.Name, err)
+			}
+			a.Members = append(a.Members, Member{Name: hdr.Name, Size: cr.n, SHA256: hex.EncodeToString(h.Sum(nil))})
+			return nil
+		}
+		n, err := io.Copy(w, r)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		a.Members = append(a.Members, Member{Name: hdr.Name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+
+		switch hdr.Name {
+		case FileMeta:
+			var m Meta
+			if err := json.Unmarshal(keep.Bytes(), &m); err != nil {
+				return fmt.Errorf("decode %s: %w", FileMeta, err)
+			}
+			a.Meta = &m
+		case FileSums:
+			a.Sums = parseSums(keep.Bytes())
+		}
+		return nil
+	})
+	return a, err
+}
+
+// countingReader counts bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// parseSums parses sha256sum output: "<hex>  <name>" (or "<hex> *<name>") per line.
+func parseSums(data []byte) map[string]string {
+	sums := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// File verification statuses.
+const (
+	StatusOK       = "ok"
+	StatusMismatch = "mismatch" // digest differs from SHA256SUMS
+	StatusMissing  = "missing"  // listed in SHA256SUMS (or required) but absent
+	StatusUnlisted = "unlisted" // present but not covered by SHA256SUMS
+)
+
+// FileStatus is the verification result of one archive file.
+type FileStatus struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Status   string `json:"status"`
+}
+
+// Report is the result of Verify.
+type Report struct {
+	Path  string       `json:"path"`
+	Size  int64        `json:"size"`
+	OK    bool         `json:"ok"`
+	Files []FileStatus `json:"files"`
+	Error string       `json:"error,omitempty"`
+}
+
+// Verify walks the archive and checks meta.json and state.bin against SHA256SUMS.
+// A structural problem (not gzip/tar, no SHA256SUMS) is returned as an error with a
+// report that records it.
+func Verify(path string) (Report, error) {
+	a, err := Scan(path, ScanOptions{})
+	return report(a, err)
+}
+
+// VerifyReader is Verify over an archive stream; the report's Path and Size are empty.
+func VerifyReader(src io.Reader) (Report, error) {
+	a, err := ScanReader(src, ScanOptions{})
+	return report(a, err)
+}
+
+// report checks the scanned members against SHA256SUMS.
+func report(a Archive, err error) (Report, error) {
+	r := Report{Path: a.Path, Size: a.Size}
+	if err != nil {
+		r.Error = err.Error()
+		return r, err
+	}
+	if a.Sums == nil {
+		err := fmt.Errorf("%s: %w", FileSums, ErrNotFound)
+		r.Error = err.Error()
+		return r, err
+	}
+
+	seen := map[string]bool{}
+	for _, m := range a.Members {
+		if m.Name == FileSums {
+			continue
+		}
+		seen[m.Name] = true
+		fs := FileStatus{Name: m.Name, Size: m.Size, SHA256: m.SHA256, Expected: a.Sums[m.Name]}
+		switch {
+		case fs.Expected == "":
+			fs.Status = StatusUnlisted
+		case fs.Expected == m.SHA256:
+			fs.Status = StatusOK
+		default:
+			fs.Status = StatusMismatch
+		}
+		r.Files = append(r.Files, fs)
+	}
+	for _, name := range requiredNames(a.Sums) {
+		if !seen[name] {
+			r.Files = append(r.Files, FileStatus{Name: name, Expected: a.Sums[name], Status: StatusMissing})
+		}
+	}
+
+	r.OK = true
+	for _, f := range r.Files {
+		// Extra files are reported but only required/listed files decide the outcome.
+		if f.Status == StatusMismatch || f.Status == StatusMissing {
+			r.OK = false
+		}
+	}
+	return r, nil
+}
+
+// requiredNames returns meta.json, state.bin and every name listed in SHA256SUMS.
+func requiredNames(sums map[string]string) []string {
+	var extra []string
+	for n := range sums {
+		if n != FileMeta && n != FileState {
+			extra = append(extra, n)
+		}
+	}
+	sort.Strings(extra)
+	return append([]string{FileMeta, FileState}, extra...)
+}
@@ -0,0 +1,77 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// SecondaryError is returned when the restore target is a replication secondary.
+type SecondaryError struct {
+	Kind    string // "dr" or "performance"
+	Mode    string
+	Primary string
+}
+
+func (e *SecondaryError) Error() string {
+	primary := e.Primary
+	if primary == "" {
+		primary = "unknown"
+	}
+	return fmt.Sprintf("target is a %s replication secondary (mode=%s, primary=%s); "+
+		"restore on the primary instead, or set RESTORE_ALLOW_SECONDARY=true to override", e.Kind, e.Mode, primary)
+}
+
+// guardReplication refuses to restore onto a DR or performance secondary unless allowed.
+// sys/replication/status is tried first; sys/health replication modes are the fallback.
+func guardReplication(ctx context.Context, vc *vault.Client, allow bool) error {
+	st, err := vc.ReplicationStatus(ctx)
+	if err != nil {
+		h, herr := vc.Health(ctx)
+		if herr != nil {
+			return fmt.Errorf("replication guard: %w (health fallback: %v)", err, herr)
+		}
+		log.Warn().Err(err).Str("action", "restore_replication").Msg("replication status unavailable; using sys/health modes")
+		st = vault.ReplicationStatus{
+			Supported:   h.DRMode != "" || h.PerfMode != "",
+			DR:          vault.ReplicationMode{Mode: h.DRMode},
+			Performance: vault.ReplicationMode{Mode: h.PerfMode},
+		}
+	}
+
+	log.Info().
+		Str("action", "restore_replication").
+		Bool("enterprise", st.Supported).
+		Str("dr_mode", st.DR.Mode).
+		Str("performance_mode", st.Performance.Mode).
+		Msg("replication status")
+
+	var se *SecondaryError
+	switch {
+	case st.DR.IsSecondary():
+		se = &SecondaryError{Kind: "dr", Mode: st.DR.Mode, Primary: st.DR.Primary()}
+	case st.Performance.IsSecondary():
+		se = &SecondaryError{Kind: "performance", Mode: st.Performance.Mode, Primary: st.Performance.Primary()}
+	default:
+		return nil
+	}
+	if allow {
+		log.Warn().
+			Str("action", "restore_replication").
+			Str("kind", se.Kind).
+			Str("mode", se.Mode).
+			Str("primary", se.Primary).
+			Msg("restoring onto a replication secondary (RESTORE_ALLOW_SECONDARY=true)")
+		return nil
+	}
+	log.Error().
+		Str("action", "restore_replication").
+		Str("kind", se.Kind).
+		Str("mode", se.Mode).
+		Str("primary", se.Primary).
+		Msg("refusing restore onto a replication secondary")
+	return se
+}
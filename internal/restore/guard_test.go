@@ -0,0 +1,67 @@
+package restore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+func TestGuardReplication(t *testing.T) {
+	const (
+		drSecondary   = `{"data":{"dr":{"mode":"secondary","primaries":[{"api_address":"https://vault-primary:8200"}]},"performance":{"mode":"disabled"}}}`
+		perfSecondary = `{"data":{"dr":{"mode":"disabled"},"performance":{"mode":"secondary","primary_cluster_addr":"https://vault-perf:8201"}}}`
+		primary       = `{"data":{"dr":{"mode":"primary"},"performance":{"mode":"disabled"}}}`
+	)
+	cases := []struct {
+		name     string
+		status   string // "" answers 404 (Vault OSS)
+		allow    bool
+		wantKind string
+		wantMsg  []string
+	}{
+		{"dr secondary refused", drSecondary, false, "dr", []string{"mode=secondary", "primary=https://vault-primary:8200", "RESTORE_ALLOW_SECONDARY"}},
+		{"performance secondary refused", perfSecondary, false, "performance", []string{"mode=secondary", "primary=https://vault-perf:8201"}},
+		{"dr secondary allowed", drSecondary, true, "", nil},
+		{"performance secondary allowed", perfSecondary, true, "", nil},
+		{"primary", primary, false, "", nil},
+		{"oss 404 not replicated", "", false, "", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/sys/replication/status" || tc.status == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(tc.status))
+			}))
+			defer srv.Close()
+			vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = guardReplication(context.Background(), vc, tc.allow)
+			if tc.wantKind == "" {
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				return
+			}
+			var se *SecondaryError
+			if !errors.As(err, &se) || se.Kind != tc.wantKind {
+				t.Fatalf("want %s SecondaryError, got %v", tc.wantKind, err)
+			}
+			for _, m := range tc.wantMsg {
+				if !strings.Contains(err.Error(), m) {
+					t.Fatalf("error %q does not mention %q", err, m)
+				}
+			}
+		})
+	}
+}
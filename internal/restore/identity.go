@@ -0,0 +1,112 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// Relation of a snapshot to the target cluster, guessed from raft peer overlap.
+// meta.json carries no cluster_id, so clusters deployed from the same chart (same
+// node IDs and addresses) look alike; only RESTORE_CONFIRM_CLUSTER_ID is authoritative.
+const (
+	RelationSameCluster  = "same-cluster"  // likely rollback: the snapshot shares raft peers with the target
+	RelationCrossCluster = "cross-cluster" // likely migration: no peer in common, needs snapshot-force
+	RelationUnknown      = "unknown"       // snapshot or target membership unavailable
+)
+
+// Identity is the peer-overlap comparison of the snapshot's raft membership with the target cluster.
+type Identity struct {
+	Relation          string
+	SnapshotPeers     []string
+	TargetPeers       []string
+	TargetClusterID   string
+	TargetClusterName string
+	// Force is the endpoint decision: true uses snapshot-force.
+	Force bool
+}
+
+// identify reads the target membership (sys/storage/raft/configuration, sys/health) and
+// compares it with the snapshot's meta.json peers.
+func identify(ctx context.Context, vc *vault.Client, sess *auth.Session, meta raftsnap.Meta, metaErr error) Identity {
+	var id Identity
+	if metaErr == nil {
+		for _, s := range meta.Configuration.Servers {
+			id.SnapshotPeers = append(id.SnapshotPeers, peerName(s.ID, s.Address))
+		}
+	}
+
+	if h, err := vc.Health(ctx); err != nil {
+		log.Warn().Err(err).Str("action", "restore_identity").Msg("target cluster id unavailable")
+	} else {
+		id.TargetClusterID, id.TargetClusterName = h.ClusterID, h.ClusterName
+	}
+
+	var rc vault.RaftConfiguration
+	err := sess.Do(ctx, func(token string) (err error) {
+		rc, err = vc.RaftConfiguration(ctx, token)
+		return err
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("action", "restore_identity").Msg("target raft configuration unavailable")
+	}
+	for _, s := range rc.Servers {
+		id.TargetPeers = append(id.TargetPeers, peerName(s.NodeID, s.Address))
+	}
+
+	id.Relation = RelationUnknown
+	if len(id.SnapshotPeers) > 0 && len(rc.Servers) > 0 {
+		id.Relation = RelationCrossCluster
+		for _, s := range meta.Configuration.Servers {
+			for _, t := range rc.Servers {
+				if (s.ID != "" && s.ID == t.NodeID) || (s.Address != "" && s.Address == t.Address) {
+					id.Relation = RelationSameCluster
+				}
+			}
+		}
+	}
+	return id
+}
+
+// decideForce picks the restore endpoint. A same-cluster rollback does not need force,
+// so a force request is dropped unless confirm names the target cluster ID: peer overlap
+// is a heuristic and must not block a confirmed restore onto a look-alike cluster.
+// A cross-cluster migration (or a forced restore of unknown relation) needs force and a
+// confirm value naming the target cluster ID.
+func decideForce(id *Identity, requested bool, confirm string) error {
+	confirm = strings.TrimSpace(confirm)
+	switch id.Relation {
+	case RelationSameCluster:
+		id.Force = requested && confirm != "" && confirm == id.TargetClusterID
+		return nil
+	case RelationUnknown:
+		if !requested {
+			id.Force = false
+			return nil
+		}
+	}
+
+	if id.TargetClusterID == "" {
+		return fmt.Errorf("%s restore needs snapshot-force, but the target cluster id is unknown (sys/health); refusing", id.Relation)
+	}
+	if confirm != id.TargetClusterID {
+		return fmt.Errorf("%s restore needs snapshot-force and overwrites cluster %q (%s): "+
+			"set RESTORE_CONFIRM_CLUSTER_ID=%s to confirm", id.Relation, id.TargetClusterName, id.TargetClusterID, id.TargetClusterID)
+	}
+	id.Force = true
+	return nil
+}
+
+// peerName renders a raft peer as "id@address".
+func peerName(id, addr string) string {
+	if addr == "" {
+		return id
+	}
+	return id + "@" + addr
+}
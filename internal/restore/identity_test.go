@@ -0,0 +1,45 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecideForce(t *testing.T) {
+	const target = "8f3c-target"
+	cases := []struct {
+		name      string
+		relation  string
+		clusterID string
+		requested bool
+		confirm   string
+		wantForce bool
+		wantErr   string
+	}{
+		{"peer overlap drops unconfirmed force", RelationSameCluster, target, true, "", false, ""},
+		{"peer overlap keeps confirmed force", RelationSameCluster, target, true, target, true, ""},
+		{"peer overlap with wrong confirm", RelationSameCluster, target, true, "other", false, ""},
+		{"migration without confirm", RelationCrossCluster, target, false, "", false, "RESTORE_CONFIRM_CLUSTER_ID=" + target},
+		{"migration with wrong confirm", RelationCrossCluster, target, true, "other", false, "RESTORE_CONFIRM_CLUSTER_ID"},
+		{"migration confirmed", RelationCrossCluster, target, false, target, true, ""},
+		{"unknown without request", RelationUnknown, target, false, "", false, ""},
+		{"unknown forced needs confirm", RelationUnknown, target, true, "", false, "RESTORE_CONFIRM_CLUSTER_ID"},
+		{"unknown forced confirmed", RelationUnknown, target, true, target, true, ""},
+		{"target id unknown", RelationCrossCluster, "", true, "x", false, "target cluster id is unknown"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := Identity{Relation: tc.relation, TargetClusterID: tc.clusterID}
+			err := decideForce(&id, tc.requested, tc.confirm)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("want error containing %q, got %v", tc.wantErr, err)
+			}
+			if id.Force != tc.wantForce {
+				t.Fatalf("force: want %v, got %v", tc.wantForce, id.Force)
+			}
+		})
+	}
+}
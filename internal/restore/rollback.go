@@ -0,0 +1,64 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/provider"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// saveRollback snapshots the target cluster next to the downloaded file and uploads it
+// under "<prefix>/<timestamp>.snap". The restore must not start if this fails.
+func saveRollback(ctx context.Context, cfg config.Config, vc *vault.Client, sess *auth.Session, p provider.Provider, restoreFile, prefix string) (string, error) {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		prefix = "rollback"
+	}
+	ts := time.Now().UTC()
+	key := path.Join(prefix, ts.Format("2006-01-02T15-04-05Z")+".snap")
+	local := restoreFile + ".rollback"
+
+	start := time.Now()
+	log.Info().Str("action", "rollback_snapshot").Str("local", local).Msg("taking safety snapshot of the target cluster")
+	var file vault.SnapshotFile
+	err := sess.Do(ctx, func(token string) error {
+		var err error
+		file, err = vc.SaveSnapshot(ctx, token, local, cfg.RetryOptions())
+		return err
+	})
+	if err == nil {
+		// A rollback point that cannot be restored is no rollback point.
+		if err = file.Validate(cfg.BackupMinSize); err != nil {
+			_ = os.Remove(local)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Str("action", "rollback_snapshot").Dur("elapsed_ms", time.Since(start)).Msg("safety snapshot failed")
+		return "", fmt.Errorf("safety snapshot: %w", err)
+	}
+	if err := p.Backup(ctx, local, key, file.Digests); err != nil {
+		log.Error().Err(err).Str("action", "rollback_snapshot").Str("remote", key).Msg("safety snapshot upload failed")
+		return "", fmt.Errorf("safety snapshot upload: %w", err)
+	}
+	// The uploaded copy is the rollback point; do not leave a multi-GB file behind.
+	if err := os.Remove(local); err != nil {
+		log.Warn().Err(err).Str("action", "rollback_snapshot").Str("local", local).Msg("cannot remove local safety snapshot")
+	}
+
+	log.Warn().
+		Str("action", "rollback_snapshot").
+		Str("provider", cfg.Provider).
+		Str("rollback_key", key).
+		Dur("elapsed_ms", time.Since(start)).
+		Msgf("safety snapshot saved; undo this restore with: operator restore %s", key)
+	return key, nil
+}
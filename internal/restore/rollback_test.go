@@ -0,0 +1,151 @@
+package restore
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// fakeProvider serves archive for every download and records uploads.
+type fakeProvider struct {
+	archive   []byte
+	uploadErr error
+	uploads   map[string]util.Digests
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) Backup(ctx context.Context, source, target string, sums util.Digests) error {
+	if _, err := os.Stat(source); err != nil {
+		return err
+	}
+	if f.uploadErr != nil {
+		return f.uploadErr
+	}
+	f.uploads[target] = sums
+	return nil
+}
+
+func (f *fakeProvider) Restore(ctx context.Context, source, target string) (util.Digests, error) {
+	return nil, os.WriteFile(target, f.archive, 0o600)
+}
+
+// archive builds a minimal gzip tar with meta.json, state.bin and SHA256SUMS.
+func archive(t *testing.T) []byte {
+	t.Helper()
+	members := [][2]string{{"meta.json", `{"Index":7}`}, {"state.bin", strings.Repeat("state", 64)}}
+	var sums strings.Builder
+	for _, m := range members {
+		h := sha256.Sum256([]byte(m[1]))
+		sums.WriteString(hex.EncodeToString(h[:]) + "  " + m[0] + "\n")
+	}
+	members = append(members, [2]string{"SHA256SUMS", sums.String()})
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, m := range members {
+		if err := tw.WriteHeader(&tar.Header{Name: m[0], Mode: 0o600, Size: int64(len(m[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(m[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRun_SafetySnapshot(t *testing.T) {
+	snap := archive(t)
+	uploadErr := errors.New("container unavailable")
+	cases := []struct {
+		name      string
+		target    []byte // what the target serves as its snapshot
+		uploadErr error
+		wantErr   error
+	}{
+		{"uploaded before restore", snap, nil, nil},
+		{"invalid snapshot aborts restore", []byte("<html>502 Bad Gateway</html>"), nil, vault.ErrInvalidSnapshot},
+		{"upload failure aborts restore", snap, uploadErr, uploadErr},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			restored := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/auth/token/lookup-self":
+					_, _ = w.Write([]byte(`{"data":{"ttl":0}}`))
+				case r.URL.Path == "/v1/sys/storage/raft/snapshot" && r.Method == http.MethodGet:
+					w.Header().Set("Content-Type", "application/x-gzip")
+					_, _ = w.Write(tc.target)
+				case r.URL.Path == "/v1/sys/storage/raft/snapshot" && r.Method == http.MethodPost:
+					restored = true
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			p := &fakeProvider{archive: snap, uploadErr: tc.uploadErr, uploads: map[string]util.Digests{}}
+			local := filepath.Join(t.TempDir(), "restored.snap")
+			cfg := config.Config{
+				VaultAddr:        srv.URL,
+				Auth:             config.AuthConfig{Method: "token", Token: "s.operator"},
+				BackupMinSize:    16,
+				RetryMaxAttempts: 1,
+			}
+			opt := Options{RemoteKey: "vault/snapshots/a.snap", LocalPath: local, SafetySnapshot: true, RollbackPrefix: "pre-restore/"}
+
+			res, err := Run(context.Background(), cfg, p, opt)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) || restored {
+					t.Fatalf("want restore aborted with %v, got err=%v restored=%v", tc.wantErr, err, restored)
+				}
+				if len(p.uploads) != 0 {
+					t.Fatalf("want no rollback upload, got %v", p.uploads)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if !restored {
+				t.Fatal("snapshot was not restored")
+			}
+			if !strings.HasPrefix(res.RollbackKey, "pre-restore/") || !strings.HasSuffix(res.RollbackKey, ".snap") {
+				t.Fatalf("unexpected rollback key %q", res.RollbackKey)
+			}
+			sums, ok := p.uploads[res.RollbackKey]
+			if !ok || len(p.uploads) != 1 {
+				t.Fatalf("rollback not uploaded under %q: %v", res.RollbackKey, p.uploads)
+			}
+			if h := sha256.Sum256(snap); sums["sha256"] != hex.EncodeToString(h[:]) {
+				t.Fatalf("rollback digests not passed to the provider: %v", sums)
+			}
+			if _, err := os.Stat(local + ".rollback"); !os.IsNotExist(err) {
+				t.Fatalf("local rollback file left behind: %v", err)
+			}
+		})
+	}
+}
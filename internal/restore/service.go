@@ -2,7 +2,9 @@ package restore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,6 +14,9 @@ import (
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/provider"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/unseal"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
 )
 
@@ -22,15 +27,41 @@ type Options struct {
 	// LocalPath is where the blob will be downloaded before pushing to Vault.
 	// If empty, defaults to "./restored.snap".
 	LocalPath string
-	// Force uses /snapshot-force (optional).
+	// Force requests /snapshot-force. It is only used when ConfirmClusterID names the
+	// target; without it, a snapshot sharing raft peers with the target is restored unforced.
 	Force bool
+	// ConfirmClusterID must equal the target's sys/health cluster_id for a forced restore.
+	ConfirmClusterID string
+	// SafetySnapshot snapshots the target cluster and uploads it under RollbackPrefix
+	// before the restore, so a wrong restore can be undone.
+	SafetySnapshot bool
+	// RollbackPrefix is the provider prefix for safety snapshots (default: rollback).
+	RollbackPrefix string
 }
 
-// Run downloads the snapshot blob to a local file, then restores it into Vault (Raft).
-func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Options) error {
+// Result describes a completed restore.
+type Result struct {
+	RemoteKey string
+	LocalPath string
+	// RollbackKey is the provider key of the safety snapshot ("" when disabled).
+	RollbackKey string
+	// Verified is true when post-restore verification ran and passed.
+	Verified bool
+	// Identity is the snapshot/target comparison and the endpoint decision.
+	Identity Identity
+	// Digests of the downloaded snapshot, computed while it was written.
+	Digests util.Digests
+}
+
+// Run checks the token can restore, downloads the snapshot blob to a local file,
+// optionally saves a rollback snapshot of the target, then restores it into Vault (Raft).
+// With RESTORE_VERIFY it waits for the cluster to serve again and returns a
+// *VerifyError naming the failed step.
+func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Options) (Result, error) {
+	var res Result
 	remote := strings.TrimSpace(opt.RemoteKey)
 	if remote == "" {
-		return fmt.Errorf("restore: remote key is empty (provide RESTORE_SOURCE or CLI arg)")
+		return res, fmt.Errorf("restore: remote key is empty (provide RESTORE_SOURCE or CLI arg)")
 	}
 
 	local := strings.TrimSpace(opt.LocalPath)
@@ -38,8 +69,57 @@ func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Option
 		local = "./restored.snap"
 	}
 	local = filepath.Clean(local)
+	res.RemoteKey, res.LocalPath = remote, local
 
-	// 1) Download from provider to local file
+	// Unseal keys are loaded up front so a missing file fails before anything is changed.
+	// Without UNSEAL_KEY_FILES the shares are read from stdin, as for operator unseal.
+	var unsealKeys []string
+	if cfg.RestoreUnseal {
+		files := cfg.UnsealKeyFiles
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+		keys, err := unseal.ReadKeys(files, os.Stdin)
+		if err != nil {
+			return res, fmt.Errorf("restore unseal: %w", err)
+		}
+		unsealKeys = keys
+	}
+
+	vc, err := vault.NewClient(cfg.VaultOptions())
+	if err != nil {
+		return res, fmt.Errorf("vault client: %w", err)
+	}
+	if err := vc.SelectNode(ctx); err != nil {
+		return res, err
+	}
+
+	// 0) Never restore onto a DR/performance secondary by accident.
+	// The status endpoint is unauthenticated, and a DR secondary rejects logins anyway.
+	if err := guardReplication(ctx, vc, cfg.RestoreAllowSecondary); err != nil {
+		return res, err
+	}
+
+	// 1) Acquire Vault token via auth provider (renewed during transfers, revoked on return)
+	sess, err := auth.Start(ctx, cfg, vc)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("action", "restore_auth").
+			Str("method", cfg.Auth.Method).
+			Msg("vault auth failed")
+		return res, err
+	}
+	defer sess.Close()
+
+	// 2) Fail fast before downloading when the token cannot restore (or take the safety snapshot)
+	for _, c := range preflightChecks(opt) {
+		if err := preflight(ctx, vc, sess, c[0], c[1]); err != nil {
+			return res, err
+		}
+	}
+
+	// 3) Download from provider to local file
 	dlStart := time.Now()
 	log.Info().
 		Str("action", "download").
@@ -47,7 +127,8 @@ func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Option
 		Str("remote", remote).
 		Str("local", local).
 		Msg("starting download")
-	if err := p.Restore(ctx, remote, local); err != nil {
+	sums, err := p.Restore(ctx, remote, local)
+	if err != nil {
 		log.Error().
 			Err(err).
 			Str("action", "download").
@@ -56,36 +137,70 @@ func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Option
 			Str("local", local).
 			Dur("elapsed_ms", time.Since(dlStart)).
 			Msg("download failed")
-		return fmt.Errorf("download from provider: %w", err)
+		return res, fmt.Errorf("download from provider: %w", err)
 	}
 	log.Info().
 		Str("action", "download").
 		Str("provider", cfg.Provider).
 		Str("remote", remote).
 		Str("local", local).
+		Str("digests", sums.String()).
 		Dur("elapsed_ms", time.Since(dlStart)).
 		Msg("download OK")
+	res.Digests = sums
 
-	// 2) Acquire Vault token via auth provider
-	token, err := auth.AcquireToken(ctx, cfg)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("action", "restore_auth").
-			Str("method", cfg.Auth.Method).
-			Msg("vault auth failed")
-		return err
+	// The snapshot's meta.json gives its raft index (verification) and peers (overlap heuristic).
+	meta, metaErr := raftsnap.ReadMeta(local)
+	if metaErr != nil {
+		log.Warn().Err(metaErr).Str("action", "restore_identity").Str("local", local).Msg("cannot read snapshot metadata")
 	}
 
-	// 3) Push snapshot into Vault (Raft)
+	// 3b) Guess rollback vs migration from peer overlap: force only when needed and confirmed
+	res.Identity = identify(ctx, vc, sess, meta, metaErr)
+	forceErr := decideForce(&res.Identity, opt.Force, opt.ConfirmClusterID)
+	log.Info().
+		Str("action", "restore_identity").
+		Str("relation", res.Identity.Relation).
+		Strs("snapshot_peers", res.Identity.SnapshotPeers).
+		Strs("target_peers", res.Identity.TargetPeers).
+		Str("target_cluster_id", res.Identity.TargetClusterID).
+		Str("target_cluster_name", res.Identity.TargetClusterName).
+		Bool("force_requested", opt.Force).
+		Bool("force", res.Identity.Force).
+		Msg("snapshot/target raft peer overlap")
+	if forceErr != nil {
+		return res, fmt.Errorf("restore identity: %w", forceErr)
+	}
+	if opt.Force && !res.Identity.Force {
+		log.Warn().Str("action", "restore_identity").Str("relation", res.Identity.Relation).Msg("snapshot shares raft peers with the target and force is not confirmed; using the standard restore endpoint")
+	}
+
+	// 4) Safety snapshot of the target cluster, uploaded before anything is overwritten
+	if opt.SafetySnapshot {
+		key, err := saveRollback(ctx, cfg, vc, sess, p, local, opt.RollbackPrefix)
+		if err != nil {
+			return res, err
+		}
+		res.RollbackKey = key
+	} else {
+		log.Warn().
+			Str("action", "rollback_snapshot").
+			Str("vault_addr", cfg.VaultAddr).
+			Msg("restoring WITHOUT a safety snapshot: the target's current data cannot be recovered afterwards (set RESTORE_SAFETY_SNAPSHOT=true)")
+	}
+
+	// 5) Push snapshot into Vault (Raft)
 	restoreStart := time.Now()
 	log.Info().
 		Str("action", "vault_restore").
 		Str("vault_addr", cfg.VaultAddr).
 		Str("local", local).
-		Bool("force", opt.Force).
+		Bool("force", res.Identity.Force).
 		Msg("starting Vault restore")
-	if err := vault.RestoreSnapshot(ctx, cfg.VaultAddr, token, local, opt.Force, cfg.RetryOptions()); err != nil {
+	err = sess.Do(ctx, func(token string) error {
+		return vc.RestoreSnapshot(ctx, token, local, res.Identity.Force, cfg.RetryOptions())
+	})
+	if err != nil {
 		log.Error().
 			Err(err).
 			Str("action", "vault_restore").
@@ -93,7 +208,7 @@ func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Option
 			Str("local", local).
 			Dur("elapsed_ms", time.Since(restoreStart)).
 			Msg("vault restore failed")
-		return fmt.Errorf("vault restore: %w", err)
+		return res, fmt.Errorf("vault restore: %w", err)
 	}
 	log.Info().
 		Str("action", "vault_restore").
@@ -102,5 +217,57 @@ func Run(ctx context.Context, cfg config.Config, p provider.Provider, opt Option
 		Dur("elapsed_ms", time.Since(restoreStart)).
 		Msg("vault restore OK")
 
+	// 5b) A forced restore from another cluster seals it with the source cluster's keys
+	if cfg.RestoreUnseal {
+		ures, err := unseal.Nodes(ctx, vc, unsealKeys)
+		if err != nil {
+			return res, err
+		}
+		unseal.WarnUnlisted(ctx, vc, ures, sess)
+	}
+
+	// 6) Optionally verify the cluster came back
+	if cfg.RestoreVerify.Enabled {
+		if err := verify(ctx, cfg, vc, meta.Index, metaErr); err != nil {
+			return res, err
+		}
+		res.Verified = true
+	}
+	return res, nil
+}
+
+// preflightChecks lists the (path, capability) pairs the restore needs with opt.
+// snapshot-force is only required when force may be used.
+func preflightChecks(opt Options) [][2]string {
+	checks := [][2]string{{vault.PolicyPathSnapshot, "update"}}
+	if opt.Force || strings.TrimSpace(opt.ConfirmClusterID) != "" {
+		checks = append(checks, [2]string{vault.PolicyPathSnapshotForce, "update"})
+	}
+	if opt.SafetySnapshot {
+		checks = append(checks, [2]string{vault.PolicyPathSnapshot, "read"})
+	}
+	return checks
+}
+
+// preflight checks the token holds capability on path. A definite denial aborts the
+// restore; an inconclusive check is logged and left to the Vault call itself.
+func preflight(ctx context.Context, vc *vault.Client, sess *auth.Session, path, capability string) error {
+	err := sess.Do(ctx, func(token string) error {
+		return vc.RequireCapabilities(ctx, token, path, capability)
+	})
+	if err == nil {
+		return nil
+	}
+	var ce *vault.CapabilityError
+	if errors.As(err, &ce) {
+		log.Error().
+			Err(err).
+			Str("action", "restore_preflight").
+			Str("path", path).
+			Strs("missing", ce.Missing).
+			Msg("missing vault capability")
+		return fmt.Errorf("restore preflight: %w", err)
+	}
+	log.Warn().Err(err).Str("action", "restore_preflight").Str("path", path).Msg("capability check skipped")
 	return nil
 }
@@ -0,0 +1,53 @@
+package restore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// A token that can restore but not force passes preflight unless force may be used.
+func TestPreflightChecks_ForceOnlyWhenRequested(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct{ Paths []string }
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		caps := []string{"update"}
+		if in.Paths[0] == vault.PolicyPathSnapshotForce {
+			caps = []string{"deny"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{in.Paths[0]: caps})
+	}))
+	defer srv.Close()
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(opt Options) error {
+		for _, c := range preflightChecks(opt) {
+			if err := vc.RequireCapabilities(context.Background(), "tok", c[0], c[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := run(Options{}); err != nil {
+		t.Fatalf("standard restore must not need snapshot-force: %v", err)
+	}
+	for _, opt := range []Options{{Force: true}, {ConfirmClusterID: "8f3c"}} {
+		err := run(opt)
+		var ce *vault.CapabilityError
+		if !errors.As(err, &ce) || ce.Path != vault.PolicyPathSnapshotForce {
+			t.Fatalf("%+v: want snapshot-force CapabilityError, got %v", opt, err)
+		}
+	}
+	if checks := preflightChecks(Options{SafetySnapshot: true}); !strings.Contains(strings.Join(checks[len(checks)-1][:], " "), "read") {
+		t.Fatalf("safety snapshot must check read: %v", checks)
+	}
+}
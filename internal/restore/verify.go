@@ -0,0 +1,154 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// Verification steps, in execution order.
+const (
+	StepHealth    = "health"
+	StepRaftIndex = "raft_index"
+	StepCanary    = "canary"
+)
+
+// VerifyError reports which post-restore check failed and why.
+type VerifyError struct {
+	Step string
+	Err  error
+}
+
+func (e *VerifyError) Error() string { return fmt.Sprintf("restore verify %s: %v", e.Step, e.Err) }
+
+func (e *VerifyError) Unwrap() error { return e.Err }
+
+// verify checks that the cluster serves again after a restore:
+// unsealed with an active leader, raft applied index at or past the snapshot index,
+// and (optionally) a canary secret readable with a fresh login.
+// snapIndex is the snapshot's raft index; metaErr explains why it is unknown.
+func verify(ctx context.Context, cfg config.Config, vc *vault.Client, snapIndex uint64, metaErr error) error {
+	v := cfg.RestoreVerify
+
+	err := runStep(ctx, StepHealth, v.HealthTimeout, func(ctx context.Context) error {
+		return poll(ctx, v.Interval, func(ctx context.Context) (string, bool) {
+			h, err := vc.Health(ctx)
+			if err != nil {
+				return err.Error(), false
+			}
+			if h.State == vault.StateSealed || h.State == vault.StateUninitialized {
+				return h.Reason(), false
+			}
+			l, err := vc.Leader(ctx)
+			if err != nil {
+				return err.Error(), false
+			}
+			if !l.HasLeader() {
+				return "unsealed, no active leader yet", false
+			}
+			return "unsealed, leader " + l.LeaderAddress, true
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Vault versions without raft_applied_index in sys/leader decode it as 0 even with
+	// an active leader; polling it would only time out.
+	indexCheck := func(ctx context.Context) error {
+		if metaErr != nil {
+			return fmt.Errorf("snapshot index unknown: %w", metaErr)
+		}
+		return poll(ctx, v.Interval, func(ctx context.Context) (string, bool) {
+			l, err := vc.Leader(ctx)
+			if err != nil {
+				return err.Error(), false
+			}
+			status := fmt.Sprintf("applied index %d, snapshot index %d", l.RaftAppliedIndex, snapIndex)
+			return status, l.RaftAppliedIndex >= snapIndex
+		})
+	}
+	if l, lerr := vc.Leader(ctx); lerr == nil && l.RaftAppliedIndex == 0 {
+		log.Warn().
+			Str("action", "restore_verify").
+			Str("step", StepRaftIndex).
+			Msg("restore verification skipped: unsupported, sys/leader reports no raft_applied_index")
+	} else if err := runStep(ctx, StepRaftIndex, v.IndexTimeout, indexCheck); err != nil {
+		return err
+	}
+
+	if v.CanaryPath == "" {
+		return nil
+	}
+	// The restored token store replaces the one used for the restore, so log in again.
+	return runStep(ctx, StepCanary, v.CanaryTimeout, func(ctx context.Context) error {
+		sess, err := auth.Start(ctx, cfg, vc)
+		if err != nil {
+			return fmt.Errorf("re-login: %w", err)
+		}
+		defer sess.Close()
+
+		var data map[string]any
+		err = sess.Do(ctx, func(token string) (err error) {
+			data, err = vc.Read(ctx, token, v.CanaryPath)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("canary %q has no data", v.CanaryPath)
+		}
+		return nil
+	})
+}
+
+// runStep runs one verification step under its own timeout and logs the outcome.
+func runStep(ctx context.Context, step string, timeout time.Duration, fn func(context.Context) error) error {
+	start := time.Now()
+	sctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := fn(sctx); err != nil {
+		log.Error().
+			Err(err).
+			Str("action", "restore_verify").
+			Str("step", step).
+			Dur("timeout", timeout).
+			Dur("elapsed_ms", time.Since(start)).
+			Msg("restore verification failed")
+		return &VerifyError{Step: step, Err: err}
+	}
+	log.Info().
+		Str("action", "restore_verify").
+		Str("step", step).
+		Dur("elapsed_ms", time.Since(start)).
+		Msg("restore verification OK")
+	return nil
+}
+
+// poll runs check every interval until it succeeds or ctx ends.
+// On timeout the last status is returned so the failure says what was observed.
+func poll(ctx context.Context, interval time.Duration, check func(context.Context) (string, bool)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		status, ok := check(ctx)
+		if ok {
+			log.Debug().Str("action", "restore_verify").Str("status", status).Msg("check passed")
+			return nil
+		}
+		log.Debug().Str("action", "restore_verify").Str("status", status).Msg("waiting")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last: %s)", ctx.Err(), status)
+		case <-t.C:
+		}
+	}
+}
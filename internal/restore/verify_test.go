@@ -0,0 +1,43 @@
+package restore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// A Vault without raft_applied_index in sys/leader skips the raft_index step instead of timing out.
+func TestVerify_SkipsRaftIndexWhenUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/health":
+			_, _ = w.Write([]byte(`{"initialized":true,"sealed":false,"cluster_id":"c1"}`))
+		case "/v1/sys/seal-status":
+			_, _ = w.Write([]byte(`{"type":"shamir","initialized":true,"sealed":false,"t":1,"n":1}`))
+		case "/v1/sys/leader":
+			_, _ = w.Write([]byte(`{"ha_enabled":true,"is_self":true,"leader_address":"http://vault-0:8200"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Config{RestoreVerify: config.VerifyConfig{
+		Enabled:       true,
+		HealthTimeout: time.Second,
+		IndexTimeout:  200 * time.Millisecond,
+		Interval:      10 * time.Millisecond,
+	}}
+	if err := verify(context.Background(), cfg, vc, 120, nil); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
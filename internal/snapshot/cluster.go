@@ -0,0 +1,155 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// Cluster describes the raft cluster a snapshot was taken from.
+type Cluster struct {
+	Leader           string             `json:"leader"`
+	Peers            []vault.RaftServer `json:"peers"`
+	RaftIndex        uint64             `json:"raft_index"`
+	RaftTerm         uint64             `json:"raft_term"`
+	VaultVersion     string             `json:"vault_version"`
+	Healthy          bool               `json:"healthy"`
+	FailureTolerance int                `json:"failure_tolerance"`
+	Voters           int                `json:"voters"`
+	HealthyVoters    int                `json:"healthy_voters"`
+	UnhealthyVoters  []string           `json:"unhealthy_voters,omitempty"`
+	QuorumLost       bool               `json:"quorum_lost"`
+	// AutopilotKnown is false when autopilot state was unavailable (voter health unknown).
+	AutopilotKnown bool `json:"autopilot_known"`
+}
+
+// inspectCluster reads the raft configuration and autopilot state.
+// Both reads are best effort: missing permissions only reduce what is recorded.
+func inspectCluster(ctx context.Context, vc *vault.Client, sess *auth.Session, health vault.Health) Cluster {
+	c := Cluster{VaultVersion: health.Version}
+
+	var rc vault.RaftConfiguration
+	err := sess.Do(ctx, func(token string) (err error) {
+		rc, err = vc.RaftConfiguration(ctx, token)
+		return err
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("action", "raft_configuration").Msg("raft configuration unavailable")
+	}
+	c.Peers = rc.Servers
+	for _, s := range rc.Servers {
+		if s.Leader {
+			c.Leader = s.NodeID
+		}
+	}
+
+	var ap vault.AutopilotState
+	err = sess.Do(ctx, func(token string) (err error) {
+		ap, err = vc.AutopilotState(ctx, token)
+		return err
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("action", "raft_autopilot").Msg("autopilot state unavailable; voter health unknown")
+		c.Voters = countVoters(rc.Servers)
+		return c
+	}
+
+	c.AutopilotKnown = true
+	c.Healthy = ap.Healthy
+	c.FailureTolerance = ap.FailureTolerance
+	if c.Leader == "" {
+		c.Leader = ap.Leader
+	}
+	if leader, ok := ap.Servers[ap.Leader]; ok {
+		c.RaftIndex = leader.LastIndex
+		c.RaftTerm = leader.LastTerm
+		if c.VaultVersion == "" {
+			c.VaultVersion = leader.Version
+		}
+	}
+	if len(c.Peers) == 0 {
+		c.Peers = peersFromAutopilot(ap)
+	}
+
+	voters := ap.Voters
+	if len(voters) == 0 {
+		for id, s := range ap.Servers {
+			if s.Status == "leader" || s.Status == "voter" {
+				voters = append(voters, id)
+			}
+		}
+	}
+	c.Voters = len(voters)
+	for _, id := range voters {
+		if s, ok := ap.Servers[id]; ok && s.Healthy {
+			c.HealthyVoters++
+		} else {
+			c.UnhealthyVoters = append(c.UnhealthyVoters, id)
+		}
+	}
+	sort.Strings(c.UnhealthyVoters)
+	c.QuorumLost = c.Voters > 0 && c.HealthyVoters < c.Voters/2+1
+	return c
+}
+
+// checkQuorum applies the quorum policy ("warn" or "fail") to the inspected cluster.
+func checkQuorum(c Cluster, policy string) error {
+	ev := log.Info()
+	problem := ""
+	switch {
+	case c.QuorumLost:
+		problem = fmt.Sprintf("raft quorum lost: %d/%d healthy voters", c.HealthyVoters, c.Voters)
+	case len(c.UnhealthyVoters) > 0:
+		problem = fmt.Sprintf("unhealthy raft voters: %v", c.UnhealthyVoters)
+	}
+	if problem != "" {
+		ev = log.Warn()
+	}
+	ev.Str("action", "raft_quorum").
+		Str("leader", c.Leader).
+		Int("peers", len(c.Peers)).
+		Int("voters", c.Voters).
+		Int("healthy_voters", c.HealthyVoters).
+		Int("failure_tolerance", c.FailureTolerance).
+		Uint64("raft_index", c.RaftIndex).
+		Uint64("raft_term", c.RaftTerm).
+		Str("vault_version", c.VaultVersion).
+		Bool("autopilot", c.AutopilotKnown).
+		Msg("raft cluster state")
+
+	if problem != "" && policy == "fail" {
+		return fmt.Errorf("cluster check: %s", problem)
+	}
+	return nil
+}
+
+func countVoters(servers []vault.RaftServer) int {
+	n := 0
+	for _, s := range servers {
+		if s.Voter {
+			n++
+		}
+	}
+	return n
+}
+
+// peersFromAutopilot builds a peer list when the raft configuration could not be read.
+func peersFromAutopilot(ap vault.AutopilotState) []vault.RaftServer {
+	peers := make([]vault.RaftServer, 0, len(ap.Servers))
+	for id, s := range ap.Servers {
+		peers = append(peers, vault.RaftServer{
+			NodeID:  id,
+			Address: s.Address,
+			Leader:  id == ap.Leader,
+			Voter:   slices.Contains(ap.Voters, id) || s.Status == "leader" || s.Status == "voter",
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].NodeID < peers[j].NodeID })
+	return peers
+}
@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+const raftConfig = `{"data":{"config":{"servers":[
+	{"node_id":"vault-0","address":"vault-0:8201","leader":true,"voter":true},
+	{"node_id":"vault-1","address":"vault-1:8201","voter":true},
+	{"node_id":"vault-2","address":"vault-2:8201","voter":true}]}}}`
+
+// autopilot renders sys/storage/raft/autopilot/state with the given voters healthy.
+func autopilot(healthy ...string) string {
+	servers := map[string]any{}
+	for i, id := range []string{"vault-0", "vault-1", "vault-2"} {
+		status := "voter"
+		if i == 0 {
+			status = "leader"
+		}
+		servers[id] = map[string]any{
+			"id": id, "status": status, "healthy": strings.Contains(strings.Join(healthy, ","), id),
+			"last_index": 420, "last_term": 7, "version": "1.15.2",
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"data": map[string]any{
+		"healthy": len(healthy) == 3, "failure_tolerance": max(0, len(healthy)-2),
+		"leader": "vault-0", "voters": []string{"vault-0", "vault-1", "vault-2"}, "servers": servers,
+	}})
+	return string(data)
+}
+
+// fakeCluster serves raft configuration and autopilot state; an empty body answers 404.
+func fakeCluster(t *testing.T, raftCfg, autopilotState string) (*vault.Client, *auth.Session) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{
+			"/v1/auth/token/lookup-self":           `{"data":{"accessor":"acc","ttl":0}}`,
+			"/v1/sys/storage/raft/configuration":   raftCfg,
+			"/v1/sys/storage/raft/autopilot/state": autopilotState,
+		}[r.URL.Path]
+		if body == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := auth.Start(context.Background(), config.Config{Auth: config.AuthConfig{Method: "token", Token: "s.test"}}, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(sess.Close)
+	return vc, sess
+}
+
+func TestInspectCluster_QuorumMath(t *testing.T) {
+	cases := []struct {
+		name      string
+		healthy   []string
+		lost      bool
+		unhealthy []string
+	}{
+		{"all healthy", []string{"vault-0", "vault-1", "vault-2"}, false, nil},
+		{"one voter down keeps quorum", []string{"vault-0", "vault-1"}, false, []string{"vault-2"}},
+		{"two voters down lose quorum", []string{"vault-0"}, true, []string{"vault-1", "vault-2"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			vc, sess := fakeCluster(t, raftConfig, autopilot(tc.healthy...))
+			c := inspectCluster(context.Background(), vc, sess, vault.Health{})
+
+			if !c.AutopilotKnown || c.Voters != 3 || c.HealthyVoters != len(tc.healthy) {
+				t.Fatalf("voters: got known=%v voters=%d healthy=%d", c.AutopilotKnown, c.Voters, c.HealthyVoters)
+			}
+			if c.QuorumLost != tc.lost {
+				t.Fatalf("quorum lost: want %v, got %v", tc.lost, c.QuorumLost)
+			}
+			if strings.Join(c.UnhealthyVoters, ",") != strings.Join(tc.unhealthy, ",") {
+				t.Fatalf("unhealthy voters: want %v, got %v", tc.unhealthy, c.UnhealthyVoters)
+			}
+			if c.Leader != "vault-0" || len(c.Peers) != 3 || c.RaftIndex != 420 || c.RaftTerm != 7 || c.VaultVersion != "1.15.2" {
+				t.Fatalf("cluster shape: %+v", c)
+			}
+		})
+	}
+}
+
+func TestInspectCluster_WithoutAutopilot(t *testing.T) {
+	vc, sess := fakeCluster(t, raftConfig, "")
+	c := inspectCluster(context.Background(), vc, sess, vault.Health{Version: "1.6.0"})
+	if c.AutopilotKnown || c.Voters != 3 || c.QuorumLost || c.Leader != "vault-0" || c.VaultVersion != "1.6.0" {
+		t.Fatalf("want config-only shape, got %+v", c)
+	}
+}
+
+func TestCheckQuorum_Policy(t *testing.T) {
+	lost := Cluster{Voters: 3, HealthyVoters: 1, QuorumLost: true, UnhealthyVoters: []string{"vault-1", "vault-2"}}
+	degraded := Cluster{Voters: 3, HealthyVoters: 2, UnhealthyVoters: []string{"vault-2"}}
+	cases := []struct {
+		name    string
+		c       Cluster
+		policy  string
+		wantErr string
+	}{
+		{"healthy fail", Cluster{Voters: 3, HealthyVoters: 3}, "fail", ""},
+		{"lost warn", lost, "warn", ""},
+		{"lost fail", lost, "fail", "raft quorum lost: 1/3 healthy voters"},
+		{"unhealthy warn", degraded, "warn", ""},
+		{"unhealthy fail", degraded, "fail", "unhealthy raft voters: [vault-2]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkQuorum(tc.c, tc.policy)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("want error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWriteClusterFile(t *testing.T) {
+	res := Result{
+		LocalPath: filepath.Join(t.TempDir(), "s.snap"),
+		RemoteKey: "vault/snapshots/2025-09-08T15-42-01Z.snap",
+		Health:    vault.Health{ClusterID: "8f3c", ClusterName: "vault-prod", State: vault.StateActive},
+		Cluster:   Cluster{Leader: "vault-0", RaftIndex: 420},
+	}
+	path, err := writeClusterFile(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if path != res.LocalPath+ClusterFileSuffix || rec.ClusterID != "8f3c" || rec.RemoteKey != res.RemoteKey || rec.Cluster.RaftIndex != 420 {
+		t.Fatalf("record: %s %+v", path, rec)
+	}
+}
@@ -0,0 +1,45 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+)
+
+// ClusterFileSuffix names the sidecar uploaded next to every snapshot ("<key>.cluster.json").
+const ClusterFileSuffix = ".cluster.json"
+
+// Record is the sidecar content: which cluster a snapshot came from and in what shape.
+type Record struct {
+	RemoteKey   string       `json:"remote_key"`
+	Timestamp   time.Time    `json:"timestamp"`
+	ClusterID   string       `json:"cluster_id"`
+	ClusterName string       `json:"cluster_name"`
+	State       string       `json:"state"`
+	Digests     util.Digests `json:"digests,omitempty"`
+	Cluster     Cluster      `json:"cluster"`
+}
+
+// writeClusterFile writes the Record of res next to the local snapshot and returns its path.
+func writeClusterFile(res Result) (string, error) {
+	rec := Record{
+		RemoteKey:   res.RemoteKey,
+		Timestamp:   res.Timestamp,
+		ClusterID:   res.Health.ClusterID,
+		ClusterName: res.Health.ClusterName,
+		State:       string(res.Health.State),
+		Digests:     res.Digests,
+		Cluster:     res.Cluster,
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := res.LocalPath + ClusterFileSuffix
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
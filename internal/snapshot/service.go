@@ -2,6 +2,7 @@ package snapshot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
 )
 
@@ -30,8 +32,20 @@ type Result struct {
 	LocalPath string
 	RemoteKey string
 	Timestamp time.Time
+	// Health is the cluster state observed by the pre-backup health gate.
+	Health vault.Health
+	// Cluster is the raft shape (peers, leader, index/term, version) the snapshot came from.
+	Cluster Cluster
+	// Digests of LocalPath computed while it streamed from Vault; handed to the provider
+	// so the file is not read again before upload.
+	Digests util.Digests
+	// ClusterFile is the local Record sidecar, uploaded as RemoteKey+ClusterFileSuffix.
+	ClusterFile string
 }
 
+// ErrSkipped is returned when the health gate skipped the backup (BACKUP_HEALTH_POLICY=skip).
+var ErrSkipped = errors.New("backup skipped")
+
 // Create takes a Vault Raft snapshot and returns where to upload it (remote key).
 func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error) {
 	var res Result
@@ -49,8 +63,23 @@ func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error)
 		}
 	}
 
-	// Acquire Vault token via auth provider (token or kubernetes, depending on cfg).
-	token, err := auth.AcquireToken(ctx, cfg)
+	vc, err := vault.NewClient(cfg.VaultOptions())
+	if err != nil {
+		return res, fmt.Errorf("vault client: %w", err)
+	}
+	if err := vc.SelectNode(ctx); err != nil {
+		return res, err
+	}
+
+	// Health gate: a sealed or uninitialized cluster is reported before any login.
+	health, err := checkHealth(ctx, vc, cfg.BackupHealthPolicy)
+	res.Health = health
+	if err != nil {
+		return res, err
+	}
+
+	// Acquire Vault token via auth provider; renewed while the snapshot streams, revoked on return.
+	sess, err := auth.Start(ctx, cfg, vc)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -59,13 +88,48 @@ func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error)
 			Msg("vault auth failed")
 		return res, err
 	}
+	defer sess.Close()
+
+	// Fail fast when the token cannot read the snapshot endpoint.
+	err = sess.Do(ctx, func(token string) error {
+		return vc.RequireCapabilities(ctx, token, vault.PolicyPathSnapshot, "read")
+	})
+	if err != nil {
+		var ce *vault.CapabilityError
+		if errors.As(err, &ce) {
+			log.Error().
+				Err(err).
+				Str("action", "snapshot_preflight").
+				Str("path", ce.Path).
+				Strs("missing", ce.Missing).
+				Msg("missing vault capability")
+			return res, fmt.Errorf("snapshot preflight: %w", err)
+		}
+		// Inconclusive (e.g. endpoint unavailable): let the snapshot call decide.
+		log.Warn().Err(err).Str("action", "snapshot_preflight").Msg("capability check skipped")
+	}
+
+	// Record the cluster shape and apply the quorum policy.
+	res.Cluster = inspectCluster(ctx, vc, sess, health)
+	if err := checkQuorum(res.Cluster, cfg.BackupQuorumPolicy); err != nil {
+		return res, err
+	}
 
 	start := time.Now()
 	log.Info().
 		Str("action", "vault_snapshot").
 		Str("local", local).
 		Msg("starting snapshot")
-	if err := vault.SaveSnapshot(ctx, cfg.VaultAddr, token, local, cfg.RetryOptions()); err != nil {
+	var file vault.SnapshotFile
+	err = sess.Do(ctx, func(token string) error {
+		var err error
+		file, err = vc.SaveSnapshot(ctx, token, local, cfg.RetryOptions())
+		return err
+	})
+	if err == nil {
+		err = discardInvalid(file, cfg.BackupMinSize)
+	}
+	if err != nil {
 		log.Error().
 			Err(err).
 			Str("action", "vault_snapshot").
@@ -77,6 +141,8 @@ func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error)
 	log.Info().
 		Str("action", "vault_snapshot").
 		Str("local", local).
+		Int64("size", file.Size).
+		Str("digests", file.Digests.String()).
 		Dur("elapsed_ms", time.Since(start)).
 		Msg("snapshot OK")
 
@@ -94,6 +160,7 @@ func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error)
 	key := filepath.ToSlash(filepath.Join(prefix, filename))
 
 	res.LocalPath = local
+	res.Digests = file.Digests
 	res.RemoteKey = key
 	res.Timestamp = ts
 
@@ -103,5 +170,51 @@ func Create(ctx context.Context, cfg config.Config, opt Options) (Result, error)
 		Str("remote_key", key).
 		Msg("generated remote key")
 
+	// Record the source cluster with the snapshot so an uploaded key can be traced back.
+	if res.ClusterFile, err = writeClusterFile(res); err != nil {
+		return res, fmt.Errorf("cluster record: %w", err)
+	}
 	return res, nil
 }
+
+// discardInvalid removes a downloaded file that is not a complete snapshot archive,
+// so it is never uploaded under a timestamped key.
+func discardInvalid(file vault.SnapshotFile, minSize int64) error {
+	err := file.Validate(minSize)
+	if err == nil {
+		return nil
+	}
+	if rerr := os.Remove(file.Path); rerr != nil && !os.IsNotExist(rerr) {
+		log.Warn().Err(rerr).Str("action", "vault_snapshot_validate").Str("local", file.Path).Msg("cannot remove invalid snapshot")
+	}
+	return err
+}
+
+// checkHealth classifies the cluster and applies the health policy ("fail" or "skip").
+func checkHealth(ctx context.Context, vc *vault.Client, policy string) (vault.Health, error) {
+	h, err := vc.Health(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("action", "vault_health").Str("vault_addr", vc.Addr()).Msg("health check failed")
+		return h, fmt.Errorf("vault health: %w", err)
+	}
+
+	ev := log.Info()
+	if !h.Ready() {
+		ev = log.Warn()
+	}
+	ev.Str("action", "vault_health").
+		Str("vault_addr", h.Addr).
+		Str("state", string(h.State)).
+		Str("cluster_name", h.ClusterName).
+		Str("cluster_id", h.ClusterID).
+		Str("version", h.Version).
+		Msg("cluster health")
+
+	if h.Ready() {
+		return h, nil
+	}
+	if policy == "skip" {
+		return h, fmt.Errorf("%w: %s", ErrSkipped, h.Reason())
+	}
+	return h, fmt.Errorf("cluster not ready: %s", h.Reason())
+}
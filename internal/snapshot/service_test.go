@@ -0,0 +1,51 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+func TestCheckHealth_Policy(t *testing.T) {
+	cases := []struct {
+		name    string
+		health  string
+		policy  string
+		wantErr bool
+		skipped bool
+	}{
+		{"active", `{"initialized":true}`, "fail", false, false},
+		{"standby", `{"initialized":true,"standby":true}`, "fail", false, false},
+		{"sealed skip", `{"initialized":true,"sealed":true}`, "skip", true, true},
+		{"sealed fail", `{"initialized":true,"sealed":true}`, "fail", true, false},
+		{"uninitialized skip", `{"initialized":false}`, "skip", true, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/sys/seal-status" {
+					_, _ = w.Write([]byte(`{"type":"shamir","t":3,"n":5}`))
+					return
+				}
+				_, _ = w.Write([]byte(tc.health))
+			}))
+			defer srv.Close()
+			vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = checkHealth(context.Background(), vc, tc.policy)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error=%v, got %v", tc.wantErr, err)
+			}
+			if errors.Is(err, ErrSkipped) != tc.skipped {
+				t.Fatalf("want ErrSkipped=%v (exit 3), got %v", tc.skipped, err)
+			}
+		})
+	}
+}
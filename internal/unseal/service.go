@@ -0,0 +1,225 @@
+// Package unseal submits Shamir unseal key shares to every Vault node listed in VAULT_ADDR.
+// Nodes are not discovered: raft membership needs a token and only names cluster addresses.
+// Key material is only ever sent to sys/unseal; it is never logged or returned in errors.
+package unseal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// NodeResult is the seal state of one node after unsealing.
+type NodeResult struct {
+	Node      string
+	Sealed    bool
+	Progress  int
+	Threshold int
+	Shares    int
+	Submitted int // key shares sent to this node
+	Err       error
+}
+
+// Result is the outcome for every configured node.
+type Result struct {
+	Nodes []NodeResult
+}
+
+// Sealed returns the nodes still sealed (or unreachable).
+func (r Result) Sealed() []string {
+	var out []string
+	for _, n := range r.Nodes {
+		if n.Sealed || n.Err != nil {
+			out = append(out, n.Node)
+		}
+	}
+	return out
+}
+
+// ReadKeys loads key shares, one per non-empty line, from files ("-" is stdin).
+// Lines starting with "#" are ignored. Duplicate shares are dropped.
+func ReadKeys(files []string, stdin io.Reader) ([]string, error) {
+	var keys []string
+	seen := map[string]bool{}
+	add := func(r io.Reader, name string) error {
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			k := strings.TrimSpace(sc.Text())
+			if k == "" || strings.HasPrefix(k, "#") || seen[k] {
+				continue
+			}
+			seen[k] = true
+			keys = append(keys, k)
+		}
+		if err := sc.Err(); err != nil {
+			return fmt.Errorf("read unseal keys from %s: %w", name, err)
+		}
+		return nil
+	}
+
+	for _, f := range files {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if f == "-" {
+			if err := add(stdin, "stdin"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		fh, err := os.Open(f)
+		if err != nil {
+			return nil, fmt.Errorf("open unseal key file: %w", err)
+		}
+		err = add(fh, f)
+		_ = fh.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no unseal keys provided (UNSEAL_KEY_FILES, arguments or stdin)")
+	}
+	return keys, nil
+}
+
+// Run unseals every node of VAULT_ADDR with keys (operator unseal).
+// Once the cluster is unsealed it logs in with the configured auth method and warns
+// about raft members VAULT_ADDR does not list.
+func Run(ctx context.Context, cfg config.Config, keys []string) (Result, error) {
+	vc, err := vault.NewClient(cfg.VaultOptions())
+	if err != nil {
+		return Result{}, fmt.Errorf("vault client: %w", err)
+	}
+	res, err := Nodes(ctx, vc, keys)
+	if err != nil {
+		return res, err
+	}
+
+	// Login needs an unsealed node, so the session only starts now.
+	sess, err := auth.Start(ctx, cfg, vc)
+	if err != nil {
+		log.Info().Err(err).Str("action", "unseal").Str("method", cfg.Auth.Method).
+			Msg("vault auth failed; raft membership not checked")
+		return res, nil
+	}
+	defer sess.Close()
+	WarnUnlisted(ctx, vc, res, sess)
+	return res, nil
+}
+
+// Nodes submits keys to sys/unseal on every node of vc until each node is unsealed.
+// Nodes already unsealed are left alone. It fails if any node stays sealed.
+func Nodes(ctx context.Context, vc *vault.Client, keys []string) (Result, error) {
+	var res Result
+	for _, node := range vc.Nodes() {
+		res.Nodes = append(res.Nodes, unsealNode(ctx, vc.AtNode(node), node, keys))
+	}
+	if sealed := res.Sealed(); len(sealed) > 0 {
+		return res, fmt.Errorf("unseal: %d of %d node(s) still sealed: %s", len(sealed), len(res.Nodes), strings.Join(sealed, ", "))
+	}
+	return res, nil
+}
+
+// WarnUnlisted reads the raft membership through an unsealed node and warns when the
+// cluster has more members than the nodes unsealed in res; those stay sealed. It is best
+// effort: without a token valid on this cluster the membership cannot be read.
+func WarnUnlisted(ctx context.Context, vc *vault.Client, res Result, sess *auth.Session) {
+	var at string
+	for _, n := range res.Nodes {
+		if !n.Sealed && n.Err == nil {
+			at = n.Node
+			break
+		}
+	}
+	if at == "" || sess == nil {
+		log.Info().Str("action", "unseal").Int("nodes", len(res.Nodes)).
+			Msg("raft membership not checked (no session); only VAULT_ADDR nodes were unsealed")
+		return
+	}
+	var rc vault.RaftConfiguration
+	err := sess.Do(ctx, func(token string) (err error) {
+		rc, err = vc.AtNode(at).RaftConfiguration(ctx, token)
+		return err
+	})
+	if err != nil {
+		log.Info().Err(err).Str("action", "unseal").Int("nodes", len(res.Nodes)).
+			Msg("raft membership not checked; only VAULT_ADDR nodes were unsealed")
+		return
+	}
+	if len(rc.Servers) <= len(res.Nodes) {
+		return
+	}
+	peers := make([]string, 0, len(rc.Servers))
+	for _, s := range rc.Servers {
+		peers = append(peers, s.NodeID+"@"+s.Address)
+	}
+	log.Warn().
+		Str("action", "unseal").
+		Int("raft_members", len(rc.Servers)).
+		Int("unsealed_nodes", len(res.Nodes)).
+		Strs("raft_peers", peers).
+		Msg("cluster has more raft members than VAULT_ADDR lists; unlisted nodes stay sealed (add them to VAULT_ADDR)")
+}
+
+// unsealNode submits shares to one node and logs progress as "t of n".
+func unsealNode(ctx context.Context, vc *vault.Client, node string, keys []string) NodeResult {
+	nr := NodeResult{Node: node}
+	st, err := vc.SealStatus(ctx)
+	if err != nil {
+		nr.Err = err
+		log.Error().Err(err).Str("action", "unseal").Str("node", node).Msg("seal status failed")
+		return nr
+	}
+	nr.Sealed, nr.Progress, nr.Threshold, nr.Shares = st.Sealed, st.Progress, st.T, st.N
+	if !st.Sealed {
+		log.Info().Str("action", "unseal").Str("node", node).Msg("node already unsealed")
+		return nr
+	}
+	if st.Type != "" && st.Type != "shamir" {
+		log.Warn().Str("action", "unseal").Str("node", node).Str("seal_type", st.Type).Msg("not a Shamir seal; key shares may be recovery keys and not apply")
+	}
+
+	for i, key := range keys {
+		st, err = vc.Unseal(ctx, key)
+		nr.Submitted++
+		if err != nil {
+			// Do not include the key; report which share (by position) was rejected.
+			nr.Err = fmt.Errorf("key share #%d rejected: %w", i+1, err)
+			log.Error().Err(nr.Err).Str("action", "unseal").Str("node", node).Msg("unseal failed")
+			return nr
+		}
+		nr.Sealed, nr.Progress, nr.Threshold, nr.Shares = st.Sealed, st.Progress, st.T, st.N
+		if !st.Sealed {
+			break
+		}
+		log.Info().
+			Str("action", "unseal").
+			Str("node", node).
+			Msgf("unseal progress %d of %d (shares: %d)", st.Progress, st.T, st.N)
+	}
+
+	ev := log.Info()
+	msg := "node unsealed"
+	if nr.Sealed {
+		ev, msg = log.Error(), "node still sealed: not enough key shares"
+	}
+	ev.Str("action", "unseal").
+		Str("node", node).
+		Int("submitted", nr.Submitted).
+		Int("threshold", nr.Threshold).
+		Int("shares", nr.Shares).
+		Msg(msg)
+	return nr
+}
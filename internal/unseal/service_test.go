@@ -0,0 +1,152 @@
+package unseal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/auth"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/config"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/vault"
+)
+
+// fakeNode is a Shamir-sealed node with threshold 2 that accepts any key except "bad".
+func fakeNode(t *testing.T, sealed bool) *httptest.Server {
+	t.Helper()
+	progress := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/unseal" {
+			var in struct{ Key string }
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			if in.Key == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid key"]}`))
+				return
+			}
+			if progress++; progress >= 2 {
+				sealed, progress = false, 0
+			}
+		}
+		_ = json.NewEncoder(w).Encode(vault.SealStatus{Type: "shamir", Initialized: true, Sealed: sealed, T: 2, N: 3, Progress: progress})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNodes_UnsealsEveryNodeWithoutLoggingKeys(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = prev }()
+
+	a, b, c := fakeNode(t, true), fakeNode(t, false), fakeNode(t, true)
+	vc, err := vault.NewClient(vault.Options{Addr: a.URL + "," + b.URL + "," + c.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ReadKeys([]string{"-"}, strings.NewReader("# shares\nsecret-share-1\n\nsecret-share-2\nsecret-share-1\nsecret-share-3\n"))
+	if err != nil || len(keys) != 3 {
+		t.Fatalf("ReadKeys: %v %v", keys, err)
+	}
+
+	res, err := Nodes(context.Background(), vc, keys)
+	if err != nil {
+		t.Fatalf("Nodes: %v", err)
+	}
+	if got := []int{res.Nodes[0].Submitted, res.Nodes[1].Submitted, res.Nodes[2].Submitted}; got[0] != 2 || got[1] != 0 || got[2] != 2 {
+		t.Fatalf("submitted per node: want [2 0 2], got %v", got)
+	}
+	if !strings.Contains(buf.String(), "unseal progress 1 of 2") {
+		t.Fatalf("progress not logged: %s", buf.String())
+	}
+
+	// Already unsealed nodes receive nothing, so even a bad share is never sent.
+	if _, err := Nodes(context.Background(), vc, []string{"bad"}); err != nil {
+		t.Fatalf("nodes are unsealed now, want no error: %v", err)
+	}
+
+	sealed := fakeNode(t, true)
+	vc2, _ := vault.NewClient(vault.Options{Addr: sealed.URL})
+	if _, err := Nodes(context.Background(), vc2, []string{"secret-share-9", "bad"}); err == nil {
+		t.Fatal("want error for rejected share")
+	}
+	if strings.Contains(buf.String(), "secret-share") {
+		t.Fatalf("key material leaked into logs: %s", buf.String())
+	}
+}
+
+func TestWarnUnlisted_ReportsRaftMembersMissingFromVaultAddr(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = prev }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"config":{"servers":[` +
+			`{"node_id":"vault-0","address":"vault-0:8201"},` +
+			`{"node_id":"vault-1","address":"vault-1:8201"},` +
+			`{"node_id":"vault-2","address":"vault-2:8201"}]}}}`))
+	}))
+	defer srv.Close()
+	vc, err := vault.NewClient(vault.Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := auth.Start(context.Background(), config.Config{Auth: config.AuthConfig{Method: "token", Token: "s.root"}}, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Close()
+
+	WarnUnlisted(context.Background(), vc, Result{Nodes: []NodeResult{{Node: srv.URL}}}, sess)
+	if out := buf.String(); !strings.Contains(out, `"raft_members":3`) || !strings.Contains(out, "vault-2@vault-2:8201") {
+		t.Fatalf("want unlisted members warning, got: %s", out)
+	}
+}
+
+func TestRun_ChecksMembershipWithConfiguredAuth(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = prev }()
+
+	var raftToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/seal-status":
+			_ = json.NewEncoder(w).Encode(vault.SealStatus{Type: "shamir", Initialized: true, T: 2, N: 3})
+		case "/v1/sys/storage/raft/configuration":
+			raftToken = r.Header.Get("X-Vault-Token")
+			_, _ = w.Write([]byte(`{"data":{"config":{"servers":[{"node_id":"vault-0"},{"node_id":"vault-1"}]}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer srv.Close()
+
+	sink := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(sink, []byte("s.agent\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{VaultAddr: srv.URL, Auth: config.AuthConfig{Method: "file", TokenPath: sink}}
+	if _, err := Run(context.Background(), cfg, []string{"unused"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if raftToken != "s.agent" {
+		t.Fatalf("raft configuration read with %q, want the file token", raftToken)
+	}
+	if !strings.Contains(buf.String(), `"raft_members":2`) {
+		t.Fatalf("want unlisted members warning, got: %s", buf.String())
+	}
+}
@@ -2,25 +2,116 @@ package util
 
 import (
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
+	"fmt"
+	"hash"
 	"io"
 	"os"
+	"sort"
+	"strings"
 )
 
-// SHA256File computes the SHA-256 checksum of a file and returns:
-//   - the hex-encoded digest
-//   - the file size in bytes
-func SHA256File(path string) (sum string, size int64, err error) {
+// DefaultDigests is used when no digest algorithm is configured.
+var DefaultDigests = []string{"sha256"}
+
+// digestFuncs are the supported digest algorithms.
+var digestFuncs = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Digests maps an algorithm name ("sha256", "sha512") to a hex-encoded digest.
+type Digests map[string]string
+
+// String renders the digests as "sha256:<hex> sha512:<hex>" in name order.
+func (d Digests) String() string {
+	names := make([]string, 0, len(d))
+	for n := range d {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, n := range names {
+		parts = append(parts, n+":"+d[n])
+	}
+	return strings.Join(parts, " ")
+}
+
+// ValidDigest reports whether name is a supported digest algorithm.
+func ValidDigest(name string) bool {
+	_, ok := digestFuncs[name]
+	return ok
+}
+
+// Hasher is an io.Writer feeding one hash per algorithm, meant to sit in a tee
+// (io.MultiWriter / io.TeeReader) so a stream is digested while it is copied.
+type Hasher struct {
+	names  []string
+	hashes []hash.Hash
+	n      int64
+}
+
+// NewHasher returns a Hasher for algs (DefaultDigests when empty).
+func NewHasher(algs []string) (*Hasher, error) {
+	if len(algs) == 0 {
+		algs = DefaultDigests
+	}
+	h := &Hasher{}
+	for _, a := range algs {
+		fn, ok := digestFuncs[a]
+		if !ok {
+			return nil, fmt.Errorf("unsupported digest %q (want sha256 or sha512)", a)
+		}
+		h.names = append(h.names, a)
+		h.hashes = append(h.hashes, fn())
+	}
+	return h, nil
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	for _, hh := range h.hashes {
+		_, _ = hh.Write(p) // hash.Hash never returns an error
+	}
+	h.n += int64(len(p))
+	return len(p), nil
+}
+
+// Reset clears the hashes so a retried transfer starts over.
+func (h *Hasher) Reset() {
+	for _, hh := range h.hashes {
+		hh.Reset()
+	}
+	h.n = 0
+}
+
+// Size returns the number of bytes written since the last Reset.
+func (h *Hasher) Size() int64 { return h.n }
+
+// Sum returns the hex digest of every algorithm.
+func (h *Hasher) Sum() Digests {
+	d := make(Digests, len(h.names))
+	for i, n := range h.names {
+		d[n] = hex.EncodeToString(h.hashes[i].Sum(nil))
+	}
+	return d
+}
+
+// DigestFile reads a file once and returns its digests and size.
+// Prefer a Hasher on the stream that produced the file; this is the fallback when none ran.
+func DigestFile(path string, algs []string) (Digests, int64, error) {
+	h, err := NewHasher(algs)
+	if err != nil {
+		return nil, 0, err
+	}
 	f, err := os.Open(path)
 	if err != nil {
-		return "", 0, err
+		return nil, 0, err
 	}
 	defer func() { _ = f.Close() }()
 
-	h := sha256.New()
-	n, err := io.Copy(h, f)
-	if err != nil {
-		return "", 0, err
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, 0, err
 	}
-	return hex.EncodeToString(h.Sum(nil)), n, nil
+	return h.Sum(), h.Size(), nil
 }
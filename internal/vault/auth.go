@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+)
+
+// PayloadFunc builds a login payload. It runs before every attempt so rotating
+// credentials (projected JWTs, sink files) are re-read.
+type PayloadFunc func(ctx context.Context) (any, error)
+
+// Login posts the payload to /v1/auth/<mount>/login and returns the client token.
+// Transient failures (network errors, 412/429/5xx, leader elections) are retried with opts,
+// honoring Retry-After; 400/401/403/404 are permanent.
+func (c *Client) Login(ctx context.Context, mount string, payload PayloadFunc, opts retry.Options) (string, error) {
+	path := "/v1/auth/" + strings.Trim(mount, "/") + "/login"
+
+	start := time.Now()
+	attempt := 0
+	var token string
+	loginOnce := func(ctx context.Context) error {
+		attempt++
+		body, err := payload(ctx)
+		if err != nil {
+			return err
+		}
+		var out struct {
+			Auth struct {
+				ClientToken string `json:"client_token"`
+			} `json:"auth"`
+		}
+		if err := c.doJSON(ctx, http.MethodPost, path, "", body, &out); err != nil {
+			log.Debug().Err(err).Str("action", "vault_login").Str("mount", mount).
+				Int("attempt", attempt).Msg("attempt failed")
+			return err
+		}
+		if out.Auth.ClientToken == "" {
+			return errors.New("empty client_token")
+		}
+		token = out.Auth.ClientToken
+		return nil
+	}
+
+	err := retry.Do(ctx, opts, isLoginRetryable, func(ctx context.Context) error {
+		return handleRetryAfter(ctx, loginOnce)
+	})
+	if err != nil {
+		return "", fmt.Errorf("vault login failed after %d attempt(s): %w", attempt, err)
+	}
+	log.Debug().Str("action", "vault_login").Str("mount", mount).Int("attempts", attempt).
+		Dur("total_elapsed_ms", time.Since(start)).Msg("login OK")
+	return token, nil
+}
+
+// isLoginRetryable returns true for transient login failures.
+func isLoginRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se httpStatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusPreconditionFailed ||
+			se.StatusCode == http.StatusTooManyRequests ||
+			se.StatusCode == http.StatusRequestTimeout ||
+			(se.StatusCode >= 500 && se.StatusCode <= 599)
+	}
+	// Connection refused/reset and timeouts while a node restarts or a leader is elected.
+	// TLS verification, DNS and URL errors are permanent.
+	return isTransientNetError(err)
+}
@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Raft snapshot paths as written in policies (no /v1/ prefix).
+const (
+	PolicyPathSnapshot      = "sys/storage/raft/snapshot"
+	PolicyPathSnapshotForce = "sys/storage/raft/snapshot-force"
+)
+
+// CapabilityError reports capabilities a token is missing on a path.
+type CapabilityError struct {
+	Path    string
+	Missing []string
+	Have    []string
+}
+
+func (e *CapabilityError) Error() string {
+	want := `"` + strings.Join(e.Missing, `", "`) + `"`
+	return fmt.Sprintf("token lacks %s capability on %s (has: [%s]); add to the role's policy: path %q { capabilities = [%s] }",
+		want, e.Path, strings.Join(e.Have, ", "), e.Path, want)
+}
+
+// CapabilitiesSelf returns the token's capabilities on path.
+func (c *Client) CapabilitiesSelf(ctx context.Context, token, path string) ([]string, error) {
+	in := map[string][]string{"paths": {path}}
+	var out map[string]any
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/sys/capabilities-self", token, in, &out); err != nil {
+		return nil, fmt.Errorf("capabilities-self: %w", err)
+	}
+
+	// Vault answers with the path as key (and "capabilities" for single-path requests).
+	raw, ok := out[path]
+	if !ok {
+		raw = out["capabilities"]
+	}
+	list, _ := raw.([]any)
+	caps := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			caps = append(caps, s)
+		}
+	}
+	return caps, nil
+}
+
+// RequireCapabilities fails with a *CapabilityError when token lacks any of caps on path.
+// Raft paths only exist in the root namespace, so they are always checked there.
+func (c *Client) RequireCapabilities(ctx context.Context, token, path string, caps ...string) error {
+	cc := c
+	if isRootOnly("/v1/" + path) {
+		cc = c.WithNamespace("")
+	}
+	have, err := cc.CapabilitiesSelf(ctx, token, path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(have, "root") {
+		return nil
+	}
+
+	var missing []string
+	for _, want := range caps {
+		if !slices.Contains(have, want) {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		return &CapabilityError{Path: path, Missing: missing, Have: have}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+)
+
+// defaultAddr is used when no Vault address is configured.
+const defaultAddr = "http://vault-hashicorp.localhost"
+
+// Per-call timeouts applied on top of the shared transport.
+const (
+	snapshotTimeout = 2 * time.Minute
+	apiTimeout      = 10 * time.Second
+)
+
+// Options configures the shared Vault HTTP client.
+type Options struct {
+	// Addr is the Vault API address (e.g. https://vault.example.com:8200).
+	// Several nodes may be given comma-separated, or as a DNS SRV name
+	// ("srv+https://_vault._tcp.example.com").
+	Addr string
+	// CACert is a PEM bundle used to verify the Vault server certificate.
+	CACert string
+	// CAPath is a directory of PEM files used when CACert is empty.
+	CAPath string
+	// TLSServerName overrides the SNI / verification host name.
+	TLSServerName string
+	// ClientCert and ClientKey are a PEM key pair presented for mTLS and cert auth.
+	ClientCert string
+	ClientKey  string
+	// SkipVerify disables TLS verification (never use in production).
+	SkipVerify bool
+	// Namespace is the Vault Enterprise namespace sent as X-Vault-Namespace.
+	Namespace string
+	// Digests are the algorithms computed while a snapshot streams to disk (default sha256).
+	Digests []string
+}
+
+// Client is the single HTTP client used for every Vault call.
+// All requests share one transport so TLS settings and connections are reused.
+type Client struct {
+	addr      string   // node used for API calls
+	nodes     []string // every configured node, probed for leader discovery
+	namespace string
+	digests   []string // snapshot digest algorithms
+	transport http.RoundTripper
+}
+
+// NewClient builds a Vault client with TLS settings loaded from opts.
+func NewClient(opts Options) (*Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+	nodes, err := parseNodes(ctx, opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = tlsCfg
+
+	if _, err := util.NewHasher(opts.Digests); err != nil {
+		return nil, err
+	}
+
+	return &Client{addr: nodes[0], nodes: nodes, namespace: normalizeNamespace(opts.Namespace), digests: opts.Digests, transport: tr}, nil
+}
+
+// Addr returns the Vault node address used for API calls (without trailing slash).
+func (c *Client) Addr() string { return c.addr }
+
+// httpClient returns an http.Client bound to the shared transport.
+func (c *Client) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: c.transport, Timeout: timeout}
+}
+
+// buildTLSConfig assembles the TLS configuration (CA bundle or directory, client cert, server name, skip verify).
+func buildTLSConfig(opts Options) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         strings.TrimSpace(opts.TLSServerName),
+		InsecureSkipVerify: opts.SkipVerify, //nolint:gosec // explicit opt-in via VAULT_SKIP_VERIFY
+	}
+
+	switch {
+	case strings.TrimSpace(opts.CACert) != "":
+		pool, err := loadCACert(opts.CACert)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	case strings.TrimSpace(opts.CAPath) != "":
+		pool, err := loadCAPath(opts.CAPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if strings.TrimSpace(opts.ClientCert) != "" || strings.TrimSpace(opts.ClientKey) != "" {
+		pair, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load VAULT_CLIENT_CERT/VAULT_CLIENT_KEY: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
+
+// loadCACert reads a PEM bundle into a new cert pool.
+func loadCACert(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read VAULT_CACERT %q: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("VAULT_CACERT %q: no PEM certificates found", path)
+	}
+	return pool, nil
+}
+
+// loadCAPath reads every PEM file of a directory into a new cert pool.
+func loadCAPath(dir string) (*x509.CertPool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read VAULT_CAPATH %q: %w", dir, err)
+	}
+	pool := x509.NewCertPool()
+	found := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		pem, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read VAULT_CAPATH file %q: %w", p, err)
+		}
+		if pool.AppendCertsFromPEM(pem) {
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("VAULT_CAPATH %q: no PEM certificates found", dir)
+	}
+	return pool, nil
+}
+
+// doJSON sends a JSON request to the Vault API and decodes the response into out (if non-nil).
+// Non-2xx responses are returned as httpStatusError with a trimmed body snippet.
+func (c *Client) doJSON(ctx context.Context, method, path, token string, in, out any) error {
+	var body io.Reader = http.NoBody
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setHeaders(req, token)
+
+	resp, err := c.httpClient(apiTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return httpStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp),
+			Body:       trimErrorBody(data),
+		}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode vault response: %w", err)
+	}
+	return nil
+}
+
+// trimErrorBody turns a Vault error body into a short message.
+// {"errors":["a","b"]} becomes "a; b"; anything else is returned trimmed.
+func trimErrorBody(data []byte) string {
+	var v struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &v); err == nil && len(v.Errors) > 0 {
+		return strings.Join(v.Errors, "; ")
+	}
+	return strings.TrimSpace(string(data))
+}
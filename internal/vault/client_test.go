@@ -0,0 +1,529 @@
+package vault
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+)
+
+var retryOnce = retry.Options{MaxAttempts: 1}
+
+// payload returns a PayloadFunc yielding a fixed body.
+func payload(v any) PayloadFunc {
+	return func(context.Context) (any, error) { return v, nil }
+}
+
+// writeServerCA writes the httptest server certificate as a PEM file and returns its path.
+func writeServerCA(t *testing.T, srv *httptest.Server, dir string) string {
+	t.Helper()
+	p := filepath.Join(dir, "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}
+	if err := os.WriteFile(p, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+	return p
+}
+
+func newTLSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.test"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// 1) CA bundle and CA directory are both trusted by the shared transport
+func TestNewClient_TrustsCACertAndCAPath(t *testing.T) {
+	srv := newTLSServer(t)
+	dir := t.TempDir()
+	caFile := writeServerCA(t, srv, dir)
+
+	for name, opts := range map[string]Options{
+		"cacert": {Addr: srv.URL, CACert: caFile},
+		"capath": {Addr: srv.URL, CAPath: dir},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(opts)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			tok, err := c.Login(context.Background(), "kubernetes", payload(map[string]string{"role": "r"}), retryOnce)
+			if err != nil {
+				t.Fatalf("Login over TLS: %v", err)
+			}
+			if tok != "s.test" {
+				t.Fatalf("want s.test, got %q", tok)
+			}
+		})
+	}
+}
+
+// 2) Without the CA the request fails verification
+func TestNewClient_UnknownCAFails(t *testing.T) {
+	srv := newTLSServer(t)
+
+	c, err := NewClient(Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Login(context.Background(), "kubernetes", payload(nil), retryOnce); err == nil {
+		t.Fatal("expected TLS verification error")
+	}
+}
+
+// 3) Unreadable or empty CA inputs produce explicit errors
+func TestNewClient_CAErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"missing cacert", Options{CACert: filepath.Join(dir, "nope.pem")}, "read VAULT_CACERT"},
+		{"invalid cacert", Options{CACert: empty}, "no PEM certificates found"},
+		{"missing capath", Options{CAPath: filepath.Join(dir, "nope")}, "read VAULT_CAPATH"},
+		{"empty capath", Options{CAPath: dir}, "no PEM certificates found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewClient(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("want error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+// 4) Namespace header is sent to namespaced paths but never to sys/storage/raft
+func TestNamespaceHeader_RootOnlyPaths(t *testing.T) {
+	got := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got[r.URL.Path] = r.Header.Get("X-Vault-Namespace")
+		switch r.URL.Path {
+		case "/v1/sys/leader":
+			_, _ = w.Write([]byte(`{"leader_address":""}`))
+		case pathSnapshotGet:
+			_, _ = w.Write([]byte("snapshot"))
+		default:
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"s.test"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{Addr: srv.URL, Namespace: "/team-a/"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.WithNamespace("team-a/auth").Login(context.Background(), "kubernetes", payload(nil), retryOnce); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := c.SaveSnapshot(context.Background(), "tok", filepath.Join(t.TempDir(), "s.snap"), retryOnce); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	if ns := got["/v1/auth/kubernetes/login"]; ns != "team-a/auth" {
+		t.Fatalf("login namespace: want team-a/auth, got %q", ns)
+	}
+	if ns := got["/v1/sys/leader"]; ns != "team-a" {
+		t.Fatalf("leader namespace: want team-a, got %q", ns)
+	}
+	if ns, ok := got[pathSnapshotGet]; !ok || ns != "" {
+		t.Fatalf("snapshot must run in root namespace, got %q (seen=%v)", ns, ok)
+	}
+}
+
+// writeClientKeyPair generates a self-signed client certificate and returns cert/key paths.
+func writeClientKeyPair(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "backup-host"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+// 5) Client certificate is presented when the listener requires mTLS
+func TestNewClient_PresentsClientCert(t *testing.T) {
+	var cn string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			cn = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.cert"}}`))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	caFile := writeServerCA(t, srv, dir)
+	certPath, keyPath := writeClientKeyPair(t, dir)
+
+	c, err := NewClient(Options{Addr: srv.URL, CACert: caFile, ClientCert: certPath, ClientKey: keyPath})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Login(context.Background(), "cert", payload(map[string]string{}), retryOnce); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if cn != "backup-host" {
+		t.Fatalf("server did not see client cert, got CN %q", cn)
+	}
+
+	if _, err := NewClient(Options{ClientCert: certPath, ClientKey: caFile}); err == nil ||
+		!strings.Contains(err.Error(), "VAULT_CLIENT_CERT") {
+		t.Fatalf("want key pair error, got %v", err)
+	}
+}
+
+// 6) Login retries transient failures (re-reading the payload) and stops on 403
+func TestLogin_RetriesTransientStopsOnForbidden(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		code := statuses[min(calls, len(statuses)-1)]
+		calls++
+		w.WriteHeader(code)
+		if code == http.StatusOK {
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"s.ok"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors":["Vault is sealed"]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{Addr: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := retry.Options{MaxAttempts: 5, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	built := 0
+	tok, err := c.Login(context.Background(), "jwt", func(context.Context) (any, error) {
+		built++
+		return map[string]string{}, nil
+	}, opts)
+	if err != nil || tok != "s.ok" {
+		t.Fatalf("want s.ok, got %q, %v", tok, err)
+	}
+	if built != 3 {
+		t.Fatalf("payload must be rebuilt per attempt: want 3, got %d", built)
+	}
+
+	statuses = []int{http.StatusForbidden}
+	calls = 0
+	_, err = c.Login(context.Background(), "jwt", payload(nil), opts)
+	if err == nil || calls != 1 {
+		t.Fatalf("403 must not be retried: calls=%d err=%v", calls, err)
+	}
+	if !strings.Contains(err.Error(), "http status 403: Vault is sealed") {
+		t.Fatalf("want trimmed error body, got %v", err)
+	}
+}
+
+// 6b) TLS verification and DNS failures are permanent; a refused connection is retried
+func TestLogin_DoesNotRetryTLSOrDNSErrors(t *testing.T) {
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer tlsSrv.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	closed.Close()
+
+	cases := []struct {
+		name  string
+		addr  string
+		tries int
+	}{
+		{"untrusted certificate", tlsSrv.URL, 1},
+		{"unknown host", "http://vault.invalid:8200", 1},
+		{"connection refused", closed.URL, 3},
+	}
+	opts := retry.Options{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewClient(Options{Addr: tc.addr})
+			if err != nil {
+				t.Fatal(err)
+			}
+			built := 0
+			_, err = c.Login(context.Background(), "jwt", func(context.Context) (any, error) {
+				built++
+				return map[string]string{}, nil
+			}, opts)
+			if err == nil {
+				t.Fatal("want login error")
+			}
+			if built != tc.tries {
+				t.Fatalf("want %d attempts, got %d (%v)", tc.tries, built, err)
+			}
+		})
+	}
+}
+
+// 7) With several nodes, a dead node is skipped and the active one is selected
+func TestSelectNode_SkipsUnreachableNode(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	var activeURL string
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ha_enabled":true,"is_self":false,"leader_address":"` + activeURL + `"}`))
+	}))
+	defer standby.Close()
+	active := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"ha_enabled":true,"is_self":true,"leader_address":"` + activeURL + `"}}`))
+	}))
+	defer active.Close()
+	activeURL = active.URL
+
+	c, err := NewClient(Options{Addr: dead.URL + ", " + standby.URL + "/," + active.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if n := len(c.Nodes()); n != 3 {
+		t.Fatalf("want 3 nodes, got %d", n)
+	}
+	if err := c.SelectNode(context.Background()); err != nil {
+		t.Fatalf("SelectNode: %v", err)
+	}
+	if c.Addr() != active.URL {
+		t.Fatalf("want active node %s, got %s", active.URL, c.Addr())
+	}
+	if got := c.discoverLeader(context.Background()); got != active.URL {
+		t.Fatalf("want leader %s, got %s", active.URL, got)
+	}
+
+	only, _ := NewClient(Options{Addr: dead.URL})
+	if err := only.SelectNode(context.Background()); err != nil {
+		t.Fatalf("single node must not be probed: %v", err)
+	}
+	if got := only.discoverLeader(context.Background()); got != dead.URL {
+		t.Fatalf("want fallback %s, got %s", dead.URL, got)
+	}
+}
+
+// 8) A proxy error page or a truncated stream is downloaded but fails validation
+func TestSaveSnapshot_ValidatesStream(t *testing.T) {
+	archive := snapshotArchive(t)
+	cases := []struct {
+		name  string
+		ctype string
+		body  []byte
+		ok    bool
+	}{
+		{"archive", "application/x-gzip", archive, true},
+		{"html page", "text/html; charset=utf-8", []byte("<html>502 Bad Gateway</html>"), false},
+		{"truncated", "application/octet-stream", archive[:len(archive)/2], false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/sys/leader" {
+					_, _ = w.Write([]byte(`{"leader_address":""}`))
+					return
+				}
+				w.Header().Set("Content-Type", tc.ctype)
+				_, _ = w.Write(tc.body)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(Options{Addr: srv.URL})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			sf, err := c.SaveSnapshot(context.Background(), "tok", filepath.Join(t.TempDir(), "s.snap"), retryOnce)
+			if err != nil {
+				t.Fatalf("SaveSnapshot: %v", err)
+			}
+			if sf.Size != int64(len(tc.body)) {
+				t.Fatalf("size: want %d, got %d", len(tc.body), sf.Size)
+			}
+			if sum := sha256.Sum256(tc.body); sf.Digests["sha256"] != hex.EncodeToString(sum[:]) {
+				t.Fatalf("sha256 not computed from the stream: %v", sf.Digests)
+			}
+			err = sf.Validate(16)
+			if tc.ok && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if !tc.ok && !errors.Is(err, ErrInvalidSnapshot) {
+				t.Fatalf("want ErrInvalidSnapshot, got %v", err)
+			}
+		})
+	}
+}
+
+// snapshotArchive builds a minimal gzip tar with meta.json, state.bin and SHA256SUMS.
+func snapshotArchive(t *testing.T) []byte {
+	t.Helper()
+	// Incompressible state so the archive is large enough to cut mid-stream.
+	state := make([]byte, 8192)
+	if _, err := rand.Read(state); err != nil {
+		t.Fatal(err)
+	}
+	members := [][2]string{{"meta.json", `{"Index":1}`}, {"state.bin", string(state)}}
+	var sums strings.Builder
+	for _, m := range members {
+		h := sha256.Sum256([]byte(m[1]))
+		sums.WriteString(hex.EncodeToString(h[:]) + "  " + m[0] + "\n")
+	}
+	members = append(members, [2]string{"SHA256SUMS", sums.String()})
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, m := range members {
+		if err := tw.WriteHeader(&tar.Header{Name: m[0], Mode: 0o600, Size: int64(len(m[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(m[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// 9) Preflight names the path and the policy snippet; raft paths are checked in the root namespace
+func TestRequireCapabilities_ErrorAndRootNamespace(t *testing.T) {
+	var gotNS []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotNS = append(gotNS, r.Header.Get("X-Vault-Namespace"))
+		_, _ = w.Write([]byte(`{"capabilities":["read"]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Options{Addr: srv.URL, Namespace: "team-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.RequireCapabilities(context.Background(), "tok", PolicyPathSnapshotForce, "update")
+	var ce *CapabilityError
+	if !errors.As(err, &ce) || ce.Path != PolicyPathSnapshotForce || len(ce.Missing) != 1 || ce.Missing[0] != "update" {
+		t.Fatalf("want CapabilityError for snapshot-force, got %v", err)
+	}
+	snippet := `path "sys/storage/raft/snapshot-force" { capabilities = ["update"] }`
+	if !strings.Contains(err.Error(), snippet) || !strings.Contains(err.Error(), "has: [read]") {
+		t.Fatalf("error lacks policy snippet: %v", err)
+	}
+	if err := c.RequireCapabilities(context.Background(), "tok", PolicyPathSnapshot, "read"); err != nil {
+		t.Fatalf("read is granted: %v", err)
+	}
+	for _, ns := range gotNS {
+		if ns != "" {
+			t.Fatalf("raft path checked in namespace %q, want root", ns)
+		}
+	}
+
+	// Namespaced paths keep the configured namespace.
+	gotNS = nil
+	_ = c.RequireCapabilities(context.Background(), "tok", "secret/data/canary", "read")
+	if len(gotNS) != 1 || gotNS[0] != "team-a" {
+		t.Fatalf("namespaced path: want team-a, got %v", gotNS)
+	}
+}
+
+// 10) sys/health bodies map to states; the query turns Vault's non-200 codes into 200
+func TestHealth_ClassifiesStates(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		param  string // query parameter Vault reads the status code from
+		code   int    // Vault's default code for this state
+		state  HealthState
+		ready  bool
+		reason string
+	}{
+		{"active", `{"initialized":true,"cluster_id":"c1"}`, "", 200, StateActive, true, ""},
+		{"standby", `{"initialized":true,"standby":true}`, "standbycode", 429, StateStandby, true, ""},
+		{"perf standby", `{"initialized":true,"standby":true,"performance_standby":true}`, "performancestandbycode", 473, StatePerfStandby, true, ""},
+		{"dr secondary", `{"initialized":true,"standby":true,"replication_dr_mode":"secondary"}`, "drsecondarycode", 472, StateDRSecondary, false, "DR secondary"},
+		{"sealed", `{"initialized":true,"sealed":true}`, "sealedcode", 503, StateSealed, false, "sealed (shamir seal, unseal progress 1/3)"},
+		{"uninitialized", `{"initialized":false,"sealed":true}`, "uninitcode", 501, StateUninitialized, false, "not initialized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/sys/seal-status" {
+					_, _ = w.Write([]byte(`{"type":"shamir","initialized":true,"sealed":true,"t":3,"n":5,"progress":1}`))
+					return
+				}
+				code := tc.code
+				if v := r.URL.Query().Get(tc.param); tc.param != "" && v != "" {
+					code, _ = strconv.Atoi(v)
+				}
+				w.WriteHeader(code)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(Options{Addr: srv.URL})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			h, err := c.Health(context.Background())
+			if err != nil {
+				t.Fatalf("Health: %v", err)
+			}
+			if h.State != tc.state || h.Ready() != tc.ready {
+				t.Fatalf("want %s (ready=%v), got %s (ready=%v)", tc.state, tc.ready, h.State, h.Ready())
+			}
+			if tc.reason != "" && !strings.Contains(h.Reason(), tc.reason) {
+				t.Fatalf("reason %q does not mention %q", h.Reason(), tc.reason)
+			}
+		})
+	}
+}
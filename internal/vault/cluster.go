@@ -0,0 +1,99 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RaftServer is one peer of sys/storage/raft/configuration.
+type RaftServer struct {
+	NodeID          string `json:"node_id"`
+	Address         string `json:"address"`
+	Leader          bool   `json:"leader"`
+	Voter           bool   `json:"voter"`
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+}
+
+// RaftConfiguration is the committed raft peer set.
+type RaftConfiguration struct {
+	Servers []RaftServer `json:"servers"`
+	Index   uint64       `json:"index"`
+}
+
+// AutopilotServer is one server entry of sys/storage/raft/autopilot/state.
+type AutopilotServer struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	NodeStatus string `json:"node_status"`
+	Status     string `json:"status"` // leader, voter, non-voter
+	Healthy    bool   `json:"healthy"`
+	LastIndex  uint64 `json:"last_index"`
+	LastTerm   uint64 `json:"last_term"`
+	Version    string `json:"version"`
+}
+
+// AutopilotState is the autopilot view of the raft cluster.
+type AutopilotState struct {
+	Healthy          bool                       `json:"healthy"`
+	FailureTolerance int                        `json:"failure_tolerance"`
+	Leader           string                     `json:"leader"`
+	Voters           []string                   `json:"voters"`
+	Servers          map[string]AutopilotServer `json:"servers"`
+}
+
+// RaftConfiguration reads sys/storage/raft/configuration (root namespace).
+func (c *Client) RaftConfiguration(ctx context.Context, token string) (RaftConfiguration, error) {
+	var out struct {
+		Data struct {
+			Config RaftConfiguration `json:"config"`
+		} `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/storage/raft/configuration", token, nil, &out); err != nil {
+		return RaftConfiguration{}, fmt.Errorf("raft configuration: %w", err)
+	}
+	return out.Data.Config, nil
+}
+
+// AutopilotState reads sys/storage/raft/autopilot/state (root namespace, Vault >= 1.7).
+func (c *Client) AutopilotState(ctx context.Context, token string) (AutopilotState, error) {
+	var out struct {
+		Data AutopilotState `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/storage/raft/autopilot/state", token, nil, &out); err != nil {
+		return AutopilotState{}, fmt.Errorf("raft autopilot state: %w", err)
+	}
+	return out.Data, nil
+}
+
+// RemovePeer removes a server from the raft configuration (sys/storage/raft/remove-peer).
+func (c *Client) RemovePeer(ctx context.Context, token, serverID string) error {
+	in := map[string]string{"server_id": serverID}
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/sys/storage/raft/remove-peer", token, in, nil); err != nil {
+		return fmt.Errorf("raft remove-peer %q: %w", serverID, err)
+	}
+	return nil
+}
+
+// JoinRequest is the body of sys/storage/raft/join, sent to the node that joins.
+type JoinRequest struct {
+	LeaderAPIAddr    string `json:"leader_api_addr"`
+	LeaderCACert     string `json:"leader_ca_cert,omitempty"`
+	LeaderClientCert string `json:"leader_client_cert,omitempty"`
+	LeaderClientKey  string `json:"leader_client_key,omitempty"`
+	Retry            bool   `json:"retry,omitempty"`
+	NonVoter         bool   `json:"non_voter,omitempty"`
+}
+
+// Join asks the configured node to join the raft cluster led by req.LeaderAPIAddr.
+// The endpoint is unauthenticated on a node that is not yet part of a cluster.
+func (c *Client) Join(ctx context.Context, req JoinRequest) (bool, error) {
+	var out struct {
+		Joined bool `json:"joined"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/sys/storage/raft/join", "", req, &out); err != nil {
+		return false, fmt.Errorf("raft join %s: %w", req.LeaderAPIAddr, err)
+	}
+	return out.Joined, nil
+}
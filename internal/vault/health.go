@@ -0,0 +1,133 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HealthState classifies a Vault node from sys/health and sys/seal-status.
+type HealthState string
+
+// Node states, from best to worst for taking a snapshot.
+const (
+	StateActive        HealthState = "active"
+	StateStandby       HealthState = "standby"
+	StatePerfStandby   HealthState = "perf-standby"
+	StateDRSecondary   HealthState = "dr-secondary"
+	StateSealed        HealthState = "sealed"
+	StateUninitialized HealthState = "uninitialized"
+)
+
+// healthQuery makes sys/health answer 200 in every state so the body can always be decoded.
+const healthQuery = "?standbycode=200&perfstandbyok=true&performancestandbycode=200" +
+	"&drsecondarycode=200&sealedcode=200&uninitcode=200"
+
+// Health is the classified state of the queried node.
+type Health struct {
+	Addr        string      `json:"addr"`
+	State       HealthState `json:"state"`
+	Initialized bool        `json:"initialized"`
+	Sealed      bool        `json:"sealed"`
+	Standby     bool        `json:"standby"`
+	PerfStandby bool        `json:"performance_standby"`
+	DRMode      string      `json:"replication_dr_mode,omitempty"`
+	PerfMode    string      `json:"replication_performance_mode,omitempty"`
+	ClusterID   string      `json:"cluster_id,omitempty"`
+	ClusterName string      `json:"cluster_name,omitempty"`
+	Version     string      `json:"version,omitempty"`
+
+	// Seal status (sys/seal-status).
+	SealType      string `json:"seal_type,omitempty"`
+	SealThreshold int    `json:"seal_threshold,omitempty"`
+	SealShares    int    `json:"seal_shares,omitempty"`
+	SealProgress  int    `json:"seal_progress,omitempty"`
+}
+
+// Ready reports whether the cluster can serve a snapshot through this node.
+// Standbys are fine: snapshot requests are sent to the leader.
+func (h Health) Ready() bool {
+	switch h.State {
+	case StateActive, StateStandby, StatePerfStandby:
+		return true
+	}
+	return false
+}
+
+// Reason explains a state that is not Ready.
+func (h Health) Reason() string {
+	switch h.State {
+	case StateUninitialized:
+		return "vault is not initialized"
+	case StateSealed:
+		return fmt.Sprintf("vault is sealed (%s seal, unseal progress %d/%d)", h.SealType, h.SealProgress, h.SealThreshold)
+	case StateDRSecondary:
+		return "node is a DR secondary; raft snapshots must be taken on the DR primary"
+	}
+	return string(h.State)
+}
+
+// Health queries sys/health and sys/seal-status on the configured node and classifies it.
+func (c *Client) Health(ctx context.Context) (Health, error) {
+	var hr struct {
+		Initialized bool   `json:"initialized"`
+		Sealed      bool   `json:"sealed"`
+		Standby     bool   `json:"standby"`
+		PerfStandby bool   `json:"performance_standby"`
+		DRMode      string `json:"replication_dr_mode"`
+		PerfMode    string `json:"replication_performance_mode"`
+		ClusterID   string `json:"cluster_id"`
+		ClusterName string `json:"cluster_name"`
+		Version     string `json:"version"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/health"+healthQuery, "", nil, &hr); err != nil {
+		return Health{}, fmt.Errorf("sys/health: %w", err)
+	}
+
+	var ss SealStatus
+	if hr.Initialized {
+		var err error
+		if ss, err = c.SealStatus(ctx); err != nil {
+			return Health{}, err
+		}
+	}
+
+	h := Health{
+		Addr:          c.addr,
+		Initialized:   hr.Initialized,
+		Sealed:        hr.Sealed,
+		Standby:       hr.Standby,
+		PerfStandby:   hr.PerfStandby,
+		DRMode:        hr.DRMode,
+		PerfMode:      hr.PerfMode,
+		ClusterID:     hr.ClusterID,
+		ClusterName:   hr.ClusterName,
+		Version:       hr.Version,
+		SealType:      ss.Type,
+		SealThreshold: ss.T,
+		SealShares:    ss.N,
+		SealProgress:  ss.Progress,
+	}
+	if h.ClusterID == "" {
+		h.ClusterID = ss.ClusterID
+	}
+	h.State = classify(h)
+	return h, nil
+}
+
+// classify maps raw health flags to a single state (most severe first).
+func classify(h Health) HealthState {
+	switch {
+	case !h.Initialized:
+		return StateUninitialized
+	case h.Sealed:
+		return StateSealed
+	case h.DRMode == "secondary":
+		return StateDRSecondary
+	case h.PerfStandby:
+		return StatePerfStandby
+	case h.Standby:
+		return StateStandby
+	}
+	return StateActive
+}
@@ -0,0 +1,35 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// LeaderStatus is the sys/leader view of the queried node.
+type LeaderStatus struct {
+	HAEnabled          bool   `json:"ha_enabled"`
+	IsSelf             bool   `json:"is_self"`
+	LeaderAddress      string `json:"leader_address"`
+	RaftCommittedIndex uint64 `json:"raft_committed_index"`
+	RaftAppliedIndex   uint64 `json:"raft_applied_index"`
+}
+
+// HasLeader reports whether the node knows an active leader.
+func (s LeaderStatus) HasLeader() bool { return s.IsSelf || s.LeaderAddress != "" }
+
+// Leader queries sys/leader (unauthenticated) on the configured node.
+func (c *Client) Leader(ctx context.Context) (LeaderStatus, error) {
+	// Vault can return either flat or wrapped responses depending on context/wrapping.
+	var out struct {
+		LeaderStatus
+		Data LeaderStatus `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/leader", "", nil, &out); err != nil {
+		return LeaderStatus{}, fmt.Errorf("sys/leader: %w", err)
+	}
+	if out.LeaderAddress == "" && out.Data.LeaderAddress != "" {
+		return out.Data, nil
+	}
+	return out.LeaderStatus, nil
+}
@@ -0,0 +1,26 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Read performs a GET on an arbitrary API path (e.g. "secret/data/canary") and returns its data.
+// KV v2 responses are unwrapped so the secret fields are returned directly.
+func (c *Client) Read(ctx context.Context, token, path string) (map[string]any, error) {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	var out struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/"+path, token, nil, &out); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if inner, ok := out.Data["data"].(map[string]any); ok {
+		if _, hasMeta := out.Data["metadata"]; hasMeta {
+			return inner, nil
+		}
+	}
+	return out.Data, nil
+}
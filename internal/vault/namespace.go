@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"net/http"
+	"strings"
+)
+
+// rootOnlyPrefixes lists API paths Vault Enterprise only serves from the root namespace.
+// Sending X-Vault-Namespace on these routes them to "<ns>/sys/..." which does not exist.
+var rootOnlyPrefixes = []string{
+	"/v1/sys/audit",
+	"/v1/sys/config/",
+	"/v1/sys/generate-root",
+	"/v1/sys/health",
+	"/v1/sys/init",
+	"/v1/sys/key-status",
+	"/v1/sys/metrics",
+	"/v1/sys/raw",
+	"/v1/sys/rekey",
+	"/v1/sys/replication",
+	"/v1/sys/rotate",
+	"/v1/sys/seal",
+	"/v1/sys/step-down",
+	"/v1/sys/storage/",
+	"/v1/sys/unseal",
+}
+
+// isRootOnly reports whether the request path must be sent to the root namespace.
+func isRootOnly(path string) bool {
+	for _, p := range rootOnlyPrefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeNamespace trims whitespace and surrounding slashes ("/team-a/" -> "team-a").
+func normalizeNamespace(ns string) string {
+	return strings.Trim(strings.TrimSpace(ns), "/")
+}
+
+// WithNamespace returns a client sharing the same transport that targets namespace ns.
+// An empty ns targets the root namespace.
+func (c *Client) WithNamespace(ns string) *Client {
+	cp := *c
+	cp.namespace = normalizeNamespace(ns)
+	return &cp
+}
+
+// Namespace returns the namespace applied to non root-only requests.
+func (c *Client) Namespace() string { return c.namespace }
+
+// setHeaders applies the token and namespace headers to a Vault request.
+// The namespace header is omitted for root-only paths such as sys/storage/raft.
+func (c *Client) setHeaders(req *http.Request, token string) {
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" && !isRootOnly(req.URL.Path) {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+}
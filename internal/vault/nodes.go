@@ -0,0 +1,158 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// srvPrefix marks a DNS SRV entry in VAULT_ADDR, e.g. "srv+https://_vault._tcp.example.com".
+// The scheme after the prefix is used for every target of the SRV record.
+const srvPrefix = "srv+"
+
+// parseNodes expands a comma-separated VAULT_ADDR into node URLs, resolving SRV entries.
+// Order is preserved; SRV targets follow the resolver's priority/weight order.
+func parseNodes(ctx context.Context, raw string) ([]string, error) {
+	var nodes []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimRight(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if !strings.HasPrefix(entry, srvPrefix) {
+			nodes = append(nodes, entry)
+			continue
+		}
+		resolved, err := lookupSRV(ctx, strings.TrimPrefix(entry, srvPrefix))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, resolved...)
+	}
+	if len(nodes) == 0 {
+		nodes = []string{defaultAddr}
+	}
+	return nodes, nil
+}
+
+// lookupSRV resolves "https://_vault._tcp.example.com" into "https://target:port" URLs.
+func lookupSRV(ctx context.Context, entry string) ([]string, error) {
+	scheme, name, ok := strings.Cut(entry, "://")
+	if !ok || (scheme != "http" && scheme != "https") || name == "" {
+		return nil, fmt.Errorf("VAULT_ADDR %q: want srv+https://<srv name>", srvPrefix+entry)
+	}
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, fmt.Errorf("resolve VAULT_ADDR srv %q: %w", name, err)
+	}
+	out := make([]string, 0, len(records))
+	for _, r := range records {
+		host := strings.TrimSuffix(r.Target, ".")
+		out = append(out, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(r.Port))))
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("resolve VAULT_ADDR srv %q: no records", name)
+	}
+	return out, nil
+}
+
+// Nodes returns the configured node addresses.
+func (c *Client) Nodes() []string { return c.nodes }
+
+// AtNode returns a copy of the client bound to one node address.
+func (c *Client) AtNode(addr string) *Client {
+	cp := *c
+	cp.addr = addr
+	return &cp
+}
+
+// nodeProbe is the sys/leader answer of one node.
+type nodeProbe struct {
+	Node    string
+	Status  LeaderStatus
+	Err     error
+	Elapsed time.Duration
+}
+
+// probeNodes queries sys/leader on every node in parallel. Results keep the configured order.
+func (c *Client) probeNodes(ctx context.Context) []nodeProbe {
+	out := make([]nodeProbe, len(c.nodes))
+	var wg sync.WaitGroup
+	for i, n := range c.nodes {
+		wg.Go(func() {
+			start := time.Now()
+			st, err := c.AtNode(n).Leader(ctx)
+			out[i] = nodeProbe{Node: n, Status: st, Err: err, Elapsed: time.Since(start)}
+		})
+	}
+	wg.Wait()
+	return out
+}
+
+// SelectNode points the client at a reachable node when several are configured:
+// the active node if it answered, otherwise the first node (in configured order) that answered.
+// It must be called before the client is shared. With a single node it does nothing.
+func (c *Client) SelectNode(ctx context.Context) error {
+	if len(c.nodes) < 2 {
+		return nil
+	}
+	var (
+		chosen *nodeProbe
+		errs   []error
+	)
+	probes := c.probeNodes(ctx)
+	for i := range probes {
+		p := &probes[i]
+		if p.Err != nil {
+			log.Warn().Err(p.Err).Str("action", "vault_node").Str("node", p.Node).Msg("node unreachable")
+			errs = append(errs, fmt.Errorf("%s: %w", p.Node, p.Err))
+			continue
+		}
+		if chosen == nil || (p.Status.IsSelf && !chosen.Status.IsSelf) {
+			chosen = p
+		}
+	}
+	if chosen == nil {
+		return fmt.Errorf("no vault node reachable: %w", errors.Join(errs...))
+	}
+	c.addr = chosen.Node
+	log.Info().
+		Str("action", "vault_node").
+		Str("node", chosen.Node).
+		Bool("active", chosen.Status.IsSelf).
+		Str("leader", chosen.Status.LeaderAddress).
+		Int("reachable", len(probes)-len(errs)).
+		Int("configured", len(probes)).
+		Dur("elapsed_ms", chosen.Elapsed).
+		Msg("vault node selected")
+	return nil
+}
+
+// discoverLeader asks every node for sys/leader and returns the elected leader's API address.
+// The first node (in configured order) that names a leader wins; the client address is the fallback.
+func (c *Client) discoverLeader(ctx context.Context) string {
+	for _, p := range c.probeNodes(ctx) {
+		if p.Err != nil {
+			log.Debug().Err(p.Err).Str("action", "vault_leader").Str("node", p.Node).Msg("leader probe failed")
+			continue
+		}
+		if la := strings.TrimSpace(p.Status.LeaderAddress); la != "" {
+			log.Info().
+				Str("action", "vault_leader").
+				Str("answered_by", p.Node).
+				Str("leader", la).
+				Dur("elapsed_ms", p.Elapsed).
+				Msg("snapshot target elected")
+			return la
+		}
+	}
+	log.Warn().Str("action", "vault_leader").Str("fallback", c.addr).Msg("leader discovery failed; using configured address")
+	return c.addr
+}
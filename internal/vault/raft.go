@@ -2,7 +2,6 @@ package vault
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,11 +12,14 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
 	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/retry"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
 )
 
 // Vault Raft endpoints.
@@ -30,9 +32,21 @@ const (
 type httpStatusError struct {
 	StatusCode int
 	RetryAfter time.Duration
+	Body       string // trimmed response body, when available
 }
 
-func (e httpStatusError) Error() string { return fmt.Sprintf("http status %d", e.StatusCode) }
+func (e httpStatusError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("http status %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("http status %d", e.StatusCode)
+}
+
+// IsStatus reports whether err carries a Vault HTTP response with the given status code.
+func IsStatus(err error, code int) bool {
+	var se httpStatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
 
 // parseRetryAfter supports seconds and HTTP-date.
 func parseRetryAfter(resp *http.Response) time.Duration {
@@ -62,69 +76,19 @@ func resolveRedirectURL(base *url.URL, loc string) string {
 	return base.ResolveReference(u).String()
 }
 
-// discoverLeader queries /v1/sys/leader and returns the leader's API address.
-// Falls back to the provided addr if discovery fails or address is empty.
-func discoverLeader(ctx context.Context, addr string, client *http.Client) string {
-	base := strings.TrimRight(addr, "/")
-	u := base + "/v1/sys/leader"
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
-	if err != nil {
-		return addr
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return addr
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return addr
-	}
-
-	// Vault can return either flat or wrapped responses depending on context/wrapping.
-	var flat struct {
-		LeaderAddress string `json:"leader_address"`
-		HAEnabled     bool   `json:"ha_enabled"`
-		IsSelf        bool   `json:"is_self"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&flat); err == nil && strings.TrimSpace(flat.LeaderAddress) != "" {
-		return flat.LeaderAddress
-	}
-
-	// If first decode failed or didn't contain leader_address, try the wrapped form.
-	req2, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
-	if err != nil {
-		return addr
-	}
-	resp2, err := client.Do(req2)
-	if err != nil {
-		return addr
-	}
-	defer func() { _ = resp2.Body.Close() }()
-
-	if resp2.StatusCode != http.StatusOK {
-		return addr
-	}
-	var wrapped struct {
-		Data struct {
-			LeaderAddress string `json:"leader_address"`
-			HAEnabled     bool   `json:"ha_enabled"`
-			IsSelf        bool   `json:"is_self"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp2.Body).Decode(&wrapped); err == nil {
-		if la := strings.TrimSpace(wrapped.Data.LeaderAddress); la != "" {
-			return la
-		}
+// isTransientNetError reports timeouts and refused/reset connections. Other transport
+// errors (TLS verification, unknown host, bad URL) do not go away on retry.
+func isTransientNetError(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
 	}
-	return addr
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
 }
 
 // isSnapshotRetryable returns true if the error should be retried.
 func isSnapshotRetryable(err error) bool {
-	var ne net.Error
-	if errors.As(err, &ne) && ne.Timeout() {
+	if isTransientNetError(err) {
 		return true
 	}
 	var se httpStatusError
@@ -161,11 +125,17 @@ func handleSnapshotRedirect(resp *http.Response, urlStr *string, attempt int) er
 }
 
 // writeSnapshotToFile writes the response body to a temp file and renames it.
-func writeSnapshotToFile(localFile string, body io.Reader, attempt int) error {
+// The stream is digested and checked as a snapshot archive on the way through, so the file is read once.
+func writeSnapshotToFile(localFile string, body io.Reader, algs []string, attempt int) (SnapshotFile, error) {
+	sf := SnapshotFile{Path: localFile}
+	hasher, err := util.NewHasher(algs)
+	if err != nil {
+		return sf, err
+	}
 	tmp := localFile + ".part"
 	out, err := os.Create(tmp)
 	if err != nil {
-		return err
+		return sf, err
 	}
 	defer func() {
 		if cerr := out.Close(); cerr != nil {
@@ -173,35 +143,48 @@ func writeSnapshotToFile(localFile string, body io.Reader, attempt int) error {
 		}
 	}()
 
-	if _, err = io.Copy(out, body); err != nil {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sf.Archive, sf.ArchiveErr = raftsnap.VerifyReader(pr)
+		// Keep draining after a failed check so the download is never blocked.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	sf.Size, err = io.Copy(io.MultiWriter(out, hasher), io.TeeReader(body, pw))
+	_ = pw.CloseWithError(err)
+	<-done
+	if err != nil {
 		log.Debug().Err(err).Str("action", "vault_snapshot_write").Int("attempt", attempt).Msg("stream copy error")
-		return err
+		return sf, err
 	}
-	return os.Rename(tmp, localFile)
+	sf.Archive.Path, sf.Archive.Size = localFile, sf.Size
+	sf.Digests = hasher.Sum()
+	return sf, os.Rename(tmp, localFile)
 }
 
 // SaveSnapshot downloads a Vault Raft snapshot to localFile.
-func SaveSnapshot(ctx context.Context, addr, token, localFile string, opts retry.Options) error {
-	if strings.TrimSpace(addr) == "" {
-		addr = "http://vault-hashicorp.localhost"
-	}
-
+// The returned SnapshotFile is not judged here; callers decide whether it is fit to keep.
+func (c *Client) SaveSnapshot(ctx context.Context, token, localFile string, opts retry.Options) (SnapshotFile, error) {
+	var sf SnapshotFile
 	if err := ensureParentDir(localFile); err != nil {
-		return err
+		return sf, err
 	}
 
 	startTotal := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
-	client := &http.Client{Timeout: 2 * time.Minute}
-	addr = discoverLeader(ctx, addr, client)
+	client := c.httpClient(snapshotTimeout)
+	addr := c.discoverLeader(ctx)
 	urlStr := strings.TrimRight(addr, "/") + pathSnapshotGet
 
 	attempt := 0
 	doOnce := func(ctx context.Context) error {
 		attempt++
-		return executeSnapshotGet(ctx, client, &urlStr, token, localFile, attempt, startTotal)
+		var err error
+		sf, err = c.executeSnapshotGet(ctx, client, &urlStr, token, localFile, attempt, startTotal)
+		return err
 	}
 
 	err := retry.Do(ctx, opts, isSnapshotRetryable, func(ctx context.Context) error {
@@ -210,12 +193,12 @@ func SaveSnapshot(ctx context.Context, addr, token, localFile string, opts retry
 	if err != nil {
 		log.Error().Err(err).Str("action", "vault_snapshot_get").Int("attempts", attempt).
 			Dur("total_elapsed_ms", time.Since(startTotal)).Msg("snapshot download failed")
-		return err
+		return sf, err
 	}
 
 	log.Debug().Str("action", "vault_snapshot_get").Int("attempts", attempt).
 		Dur("total_elapsed_ms", time.Since(startTotal)).Str("local", localFile).Msg("snapshot download OK")
-	return nil
+	return sf, nil
 }
 
 // ensureParentDir creates the parent directory if it doesn't exist.
@@ -229,40 +212,40 @@ func ensureParentDir(localFile string) error {
 }
 
 // executeSnapshotGet performs a single snapshot GET request.
-func executeSnapshotGet(ctx context.Context, client *http.Client, urlStr *string, token, localFile string, attempt int, startTotal time.Time) error {
+func (c *Client) executeSnapshotGet(ctx context.Context, client *http.Client, urlStr *string, token, localFile string, attempt int, startTotal time.Time) (SnapshotFile, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *urlStr, http.NoBody)
 	if err != nil {
-		return err
-	}
-	if token != "" {
-		req.Header.Set("X-Vault-Token", token)
+		return SnapshotFile{}, err
 	}
+	c.setHeaders(req, token)
 
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Debug().Err(err).Str("action", "vault_snapshot_get").Int("attempt", attempt).Msg("request error")
-		return err
+		return SnapshotFile{}, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if err := handleSnapshotRedirect(resp, urlStr, attempt); err != nil {
-		return err
+		return SnapshotFile{}, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		retryAfter := parseRetryAfter(resp)
 		log.Debug().Int("status", resp.StatusCode).Dur("retry_after", retryAfter).
 			Str("action", "vault_snapshot_get").Int("attempt", attempt).Msg("non-200 response")
-		return httpStatusError{StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+		return SnapshotFile{}, httpStatusError{StatusCode: resp.StatusCode, RetryAfter: retryAfter}
 	}
 
-	if err := writeSnapshotToFile(localFile, resp.Body, attempt); err != nil {
-		return err
+	sf, err := writeSnapshotToFile(localFile, resp.Body, c.digests, attempt)
+	if err != nil {
+		return sf, err
 	}
+	sf.ContentType = resp.Header.Get("Content-Type")
 
 	log.Debug().Str("action", "vault_snapshot_get").Int("attempt", attempt).
 		Dur("elapsed_ms", time.Since(startTotal)).Msg("attempt succeeded")
-	return nil
+	return sf, nil
 }
 
 // handleRetryAfter handles Retry-After header by sleeping before retry.
@@ -283,17 +266,13 @@ func handleRetryAfter(ctx context.Context, fn func(context.Context) error) error
 
 // RestoreSnapshot uploads a snapshot to Vault Raft.
 // If force is true, uses /snapshot-force (optional for DR tests).
-func RestoreSnapshot(ctx context.Context, addr, token, localFile string, force bool, opts retry.Options) error {
-	if strings.TrimSpace(addr) == "" {
-		addr = "http://vault-hashicorp.localhost"
-	}
-
+func (c *Client) RestoreSnapshot(ctx context.Context, token, localFile string, force bool, opts retry.Options) error {
 	startTotal := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
-	client := &http.Client{Timeout: 2 * time.Minute}
-	addr = discoverLeader(ctx, addr, client)
+	client := c.httpClient(snapshotTimeout)
+	addr := c.discoverLeader(ctx)
 
 	path := pathSnapshotPost
 	if force {
@@ -304,7 +283,7 @@ func RestoreSnapshot(ctx context.Context, addr, token, localFile string, force b
 	attempt := 0
 	doOnce := func(ctx context.Context) error {
 		attempt++
-		return executeSnapshotPost(ctx, client, &urlStr, token, localFile, attempt, startTotal)
+		return c.executeSnapshotPost(ctx, client, &urlStr, token, localFile, attempt, startTotal)
 	}
 
 	err := retry.Do(ctx, opts, isSnapshotRetryable, func(ctx context.Context) error {
@@ -322,7 +301,7 @@ func RestoreSnapshot(ctx context.Context, addr, token, localFile string, force b
 }
 
 // executeSnapshotPost performs a single snapshot POST request.
-func executeSnapshotPost(ctx context.Context, client *http.Client, urlStr *string, token, localFile string, attempt int, startTotal time.Time) error {
+func (c *Client) executeSnapshotPost(ctx context.Context, client *http.Client, urlStr *string, token, localFile string, attempt int, startTotal time.Time) error {
 	f, err := os.Open(localFile)
 	if err != nil {
 		return err
@@ -333,9 +312,7 @@ func executeSnapshotPost(ctx context.Context, client *http.Client, urlStr *strin
 		_ = f.Close()
 		return err
 	}
-	if token != "" {
-		req.Header.Set("X-Vault-Token", token)
-	}
+	c.setHeaders(req, token)
 	req.Header.Set("Content-Type", "application/octet-stream")
 
 	resp, err := client.Do(req)
@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ReplicationPrimary is a primary cluster known to a secondary.
+type ReplicationPrimary struct {
+	APIAddress     string `json:"api_address"`
+	ClusterAddress string `json:"cluster_address"`
+}
+
+// ReplicationMode is the status of one replication type (DR or performance).
+type ReplicationMode struct {
+	Mode               string               `json:"mode"` // "disabled", "primary", "secondary", "bootstrapping"
+	State              string               `json:"state"`
+	ClusterID          string               `json:"cluster_id"`
+	PrimaryClusterAddr string               `json:"primary_cluster_addr"`
+	Primaries          []ReplicationPrimary `json:"primaries"`
+}
+
+// IsSecondary reports whether the cluster is (or is becoming) a secondary.
+func (m ReplicationMode) IsSecondary() bool {
+	return strings.Contains(m.Mode, "secondary") || m.Mode == "bootstrapping"
+}
+
+// Primary returns the best known primary address (API address first).
+func (m ReplicationMode) Primary() string {
+	for _, p := range m.Primaries {
+		if p.APIAddress != "" {
+			return p.APIAddress
+		}
+	}
+	for _, p := range m.Primaries {
+		if p.ClusterAddress != "" {
+			return p.ClusterAddress
+		}
+	}
+	return m.PrimaryClusterAddr
+}
+
+// ReplicationStatus is sys/replication/status. Supported is false on Vault OSS.
+type ReplicationStatus struct {
+	Supported   bool
+	DR          ReplicationMode `json:"dr"`
+	Performance ReplicationMode `json:"performance"`
+}
+
+// ReplicationStatus queries sys/replication/status (unauthenticated, Enterprise only).
+// A 404 means the server has no replication (OSS) and is not an error.
+func (c *Client) ReplicationStatus(ctx context.Context) (ReplicationStatus, error) {
+	var out struct {
+		Data ReplicationStatus `json:"data"`
+	}
+	err := c.doJSON(ctx, http.MethodGet, "/v1/sys/replication/status", "", nil, &out)
+	if IsStatus(err, http.StatusNotFound) {
+		return ReplicationStatus{}, nil
+	}
+	if err != nil {
+		return ReplicationStatus{}, fmt.Errorf("sys/replication/status: %w", err)
+	}
+	out.Data.Supported = true
+	return out.Data, nil
+}
@@ -0,0 +1,38 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SealStatus is sys/seal-status (and the sys/unseal response).
+type SealStatus struct {
+	Type        string `json:"type"`
+	Initialized bool   `json:"initialized"`
+	Sealed      bool   `json:"sealed"`
+	T           int    `json:"t"` // threshold
+	N           int    `json:"n"` // shares
+	Progress    int    `json:"progress"`
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+}
+
+// SealStatus queries sys/seal-status (unauthenticated) on the configured node.
+func (c *Client) SealStatus(ctx context.Context) (SealStatus, error) {
+	var s SealStatus
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/sys/seal-status", "", nil, &s); err != nil {
+		return SealStatus{}, fmt.Errorf("sys/seal-status: %w", err)
+	}
+	return s, nil
+}
+
+// Unseal submits one unseal key share to sys/unseal and returns the resulting progress.
+// The key is never included in returned errors.
+func (c *Client) Unseal(ctx context.Context, key string) (SealStatus, error) {
+	var s SealStatus
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/sys/unseal", "", map[string]string{"key": key}, &s); err != nil {
+		return SealStatus{}, fmt.Errorf("sys/unseal: %w", err)
+	}
+	return s, nil
+}
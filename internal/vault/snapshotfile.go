@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/raftsnap"
+	"github.com/Chapsvision-dev/vault-raft-backup-restore/internal/util"
+)
+
+// ErrInvalidSnapshot marks a downloaded snapshot that must not be kept or uploaded.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// snapshotContentTypes are the Content-Types a snapshot response may carry.
+// Vault does not set one, so net/http sniffs the gzip header; proxies may rewrite it.
+var snapshotContentTypes = map[string]bool{
+	"":                         true,
+	"application/gzip":         true,
+	"application/x-gzip":       true,
+	"application/octet-stream": true,
+}
+
+// SnapshotFile describes a snapshot as it was streamed to disk.
+type SnapshotFile struct {
+	Path        string
+	Size        int64
+	ContentType string // Content-Type of the Vault response
+	// Digests of the file, computed from the same stream (algorithms from Options.Digests).
+	Digests util.Digests
+	// Archive is the gzip/tar and SHA256SUMS check run on the stream while it was written;
+	// ArchiveErr is set when the stream is not a snapshot archive at all.
+	Archive    raftsnap.Report
+	ArchiveErr error
+}
+
+// Validate rejects a response that is not a complete snapshot archive: an unexpected
+// Content-Type (e.g. a proxy's HTML error page), a file smaller than minSize, a broken
+// gzip/tar stream, a missing SHA256SUMS or a member that does not match it.
+func (f SnapshotFile) Validate(minSize int64) error {
+	ct := strings.TrimSpace(f.ContentType)
+	if ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil {
+			ct = mt
+		}
+	}
+	if !snapshotContentTypes[strings.ToLower(ct)] {
+		return fmt.Errorf("%w: unexpected content type %q", ErrInvalidSnapshot, f.ContentType)
+	}
+	if f.Size < minSize {
+		return fmt.Errorf("%w: %d bytes, below the %d byte minimum", ErrInvalidSnapshot, f.Size, minSize)
+	}
+	if f.ArchiveErr != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, f.ArchiveErr)
+	}
+	if !f.Archive.OK {
+		var bad []string
+		for _, fs := range f.Archive.Files {
+			if fs.Status == raftsnap.StatusMismatch || fs.Status == raftsnap.StatusMissing {
+				bad = append(bad, fs.Name+" "+fs.Status)
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrInvalidSnapshot, strings.Join(bad, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TokenInfo is the subset of auth/token/lookup-self used by the operator.
+type TokenInfo struct {
+	Accessor    string
+	DisplayName string
+	Policies    []string
+	TTL         time.Duration
+	Renewable   bool
+}
+
+// LookupSelf returns metadata about token (never its value).
+func (c *Client) LookupSelf(ctx context.Context, token string) (TokenInfo, error) {
+	var out struct {
+		Data struct {
+			Accessor    string   `json:"accessor"`
+			DisplayName string   `json:"display_name"`
+			Policies    []string `json:"policies"`
+			TTL         int64    `json:"ttl"`
+			Renewable   bool     `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/auth/token/lookup-self", token, nil, &out); err != nil {
+		return TokenInfo{}, fmt.Errorf("token lookup-self: %w", err)
+	}
+	return TokenInfo{
+		Accessor:    out.Data.Accessor,
+		DisplayName: out.Data.DisplayName,
+		Policies:    out.Data.Policies,
+		TTL:         time.Duration(out.Data.TTL) * time.Second,
+		Renewable:   out.Data.Renewable,
+	}, nil
+}
+
+// RenewSelf extends token by increment and returns the new TTL granted by Vault.
+func (c *Client) RenewSelf(ctx context.Context, token string, increment time.Duration) (time.Duration, error) {
+	in := map[string]string{"increment": fmt.Sprintf("%ds", int64(increment.Seconds()))}
+	var out struct {
+		Auth struct {
+			LeaseDuration int64 `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/auth/token/renew-self", token, in, &out); err != nil {
+		return 0, fmt.Errorf("token renew-self: %w", err)
+	}
+	return time.Duration(out.Auth.LeaseDuration) * time.Second, nil
+}
+
+// RevokeSelf revokes token (and its children).
+func (c *Client) RevokeSelf(ctx context.Context, token string) error {
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/auth/token/revoke-self", token, nil, nil); err != nil {
+		return fmt.Errorf("token revoke-self: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Unwrap exchanges a response-wrapping token for the wrapped response data.
+// The wrapping token is single use: a second call with the same token fails.
+func (c *Client) Unwrap(ctx context.Context, wrappingToken string, out any) error {
+	var resp struct {
+		Data any `json:"data"`
+	}
+	resp.Data = out
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/sys/wrapping/unwrap", wrappingToken, nil, &resp); err != nil {
+		return fmt.Errorf("vault unwrap failed: %w", err)
+	}
+	return nil
+}